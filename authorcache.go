@@ -0,0 +1,290 @@
+package main
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/catalog/disk"
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+	"github.com/projectdiscovery/templates-stats/pkg/templatestats"
+	sliceutil "github.com/projectdiscovery/utils/slice"
+	stringsutil "github.com/projectdiscovery/utils/strings"
+)
+
+type AuthorStats struct {
+	Count    int
+	CVECount int
+	Tags     map[string]int
+	LastSeen time.Time
+}
+
+// cachedTemplate is the contribution of a single template to the author stats
+type cachedTemplate struct {
+	ModTime time.Time
+	Authors []string
+	CVE     bool
+	Tags    []string
+	// Excluded templates are helpers or filtered out, they are cached so
+	// they are not parsed again but do not contribute to the stats.
+	Excluded bool
+}
+
+// authorStatsCache is the gob encoded content of the -author-stats-cache file
+type authorStatsCache struct {
+	WrittenAt time.Time
+	// Filters are the helper pattern and filters the cache was computed with
+	Filters   string
+	Authors   map[string]*AuthorStats
+	Templates map[string]cachedTemplate
+}
+
+// newAuthorStatsCache returns an empty cache
+func newAuthorStatsCache() *authorStatsCache {
+	return &authorStatsCache{Authors: make(map[string]*AuthorStats), Templates: make(map[string]cachedTemplate)}
+}
+
+// authorCacheFilters returns the settings of cfg which change the author
+// stats, a cache computed with other settings is discarded.
+func authorCacheFilters(cfg StatsConfig) string {
+	return fmt.Sprintf("%s|%v|%v|%v", cfg.HelperPattern, cfg.FilterSeverities, cfg.FilterTags, cfg.ExcludeTags)
+}
+
+// loadAuthorStatsCache reads the cache at path. A missing file returns an
+// empty cache so every template is computed on the first run.
+func loadAuthorStatsCache(path string) (*authorStatsCache, error) {
+	cache := newAuthorStatsCache()
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open author stats cache")
+	}
+	defer f.Close()
+
+	if err := gob.NewDecoder(f).Decode(cache); err != nil {
+		return nil, errors.Wrap(err, "could not decode author stats cache")
+	}
+	return cache, nil
+}
+
+func saveAuthorStatsCache(path string, cache *authorStatsCache) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "could not create author stats cache")
+	}
+	defer f.Close()
+
+	if err := gob.NewEncoder(f).Encode(cache); err != nil {
+		return errors.Wrap(err, "could not encode author stats cache")
+	}
+	return nil
+}
+
+// add caches the template at path and adds its contribution to the author stats
+func (c *authorStatsCache) add(path string, template cachedTemplate) {
+	c.Templates[path] = template
+	if template.Excluded {
+		return
+	}
+	for _, author := range template.Authors {
+		stats, ok := c.Authors[author]
+		if !ok {
+			stats = &AuthorStats{Tags: make(map[string]int)}
+			c.Authors[author] = stats
+		}
+		stats.Count++
+		if template.CVE {
+			stats.CVECount++
+		}
+		for _, tag := range template.Tags {
+			stats.Tags[tag]++
+		}
+		if template.ModTime.After(stats.LastSeen) {
+			stats.LastSeen = template.ModTime
+		}
+	}
+}
+
+// remove drops the template at path from the cache and removes its
+// contribution from the author stats.
+func (c *authorStatsCache) remove(path string) {
+	template, ok := c.Templates[path]
+	if !ok {
+		return
+	}
+	delete(c.Templates, path)
+	if template.Excluded {
+		return
+	}
+	for _, author := range template.Authors {
+		stats, ok := c.Authors[author]
+		if !ok {
+			continue
+		}
+		stats.Count--
+		if template.CVE {
+			stats.CVECount--
+		}
+		for _, tag := range template.Tags {
+			if stats.Tags[tag]--; stats.Tags[tag] <= 0 {
+				delete(stats.Tags, tag)
+			}
+		}
+		if stats.Count <= 0 {
+			delete(c.Authors, author)
+			continue
+		}
+		if !template.ModTime.Before(stats.LastSeen) {
+			stats.LastSeen = c.lastSeen(author)
+		}
+	}
+}
+
+// lastSeen returns the latest modification time of the cached templates of author
+func (c *authorStatsCache) lastSeen(author string) time.Time {
+	var lastSeen time.Time
+	for _, template := range c.Templates {
+		if template.Excluded || !sliceutil.Contains(template.Authors, author) {
+			continue
+		}
+		if template.ModTime.After(lastSeen) {
+			lastSeen = template.ModTime
+		}
+	}
+	return lastSeen
+}
+
+// parseCachedTemplate parses the template at path into its author
+// contribution, templates left out by the filters of cfg are excluded.
+func parseCachedTemplate(path string, modTime time.Time, cfg StatsConfig) (cachedTemplate, bool) {
+	data, err := parseTemplateFile(path)
+	if err != nil {
+		log.Printf("Could not parse %s: %s\n", path, err)
+		return cachedTemplate{}, false
+	}
+	infoMap, ok := data["info"].(map[interface{}]interface{})
+	if !ok || data["id"] == nil {
+		return cachedTemplate{}, false
+	}
+	if !cfg.IncludesTemplate(data) {
+		return cachedTemplate{ModTime: modTime, Excluded: true}, true
+	}
+	record := templateRecord{Path: path, ID: types.ToString(data["id"]), Data: data, Info: infoMap}
+	var tags []string
+	for _, tag := range templatestats.TemplateTags(record) {
+		if !cfg.ExcludesTag(tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return cachedTemplate{ModTime: modTime, Authors: templateAuthors(record), CVE: isCveTemplate(record), Tags: tags}, true
+}
+
+// updateAuthorStatsCache recomputes the author stats of the templates added,
+// modified or removed since the cache was written. The helper pattern and
+// filters of cfg are applied the same way as for the stats.
+func updateAuthorStatsCache(cfg StatsConfig, cache *authorStatsCache) error {
+	directory, err := filepath.Abs(cfg.TemplateDirectory)
+	if err != nil {
+		return errors.Wrap(err, "could not resolve template directory")
+	}
+	catalogClient := disk.NewCatalog(directory)
+	includedTemplates, err := catalogClient.GetTemplatePath(directory)
+	if err != nil {
+		return errors.Wrap(err, "could not get templates")
+	}
+	if filters := authorCacheFilters(cfg); cache.Filters != filters {
+		*cache = *newAuthorStatsCache()
+		cache.Filters = filters
+	}
+
+	now := time.Now()
+	present := make(map[string]struct{}, len(includedTemplates))
+	reused, parsed := 0, 0
+	for _, template := range includedTemplates {
+		if !stringsutil.EqualFoldAny(filepath.Ext(template), ".yaml") {
+			continue
+		}
+		if cfg.IsHelper(stringsutil.TrimPrefixAny(template, directory, "/", "\\")) {
+			continue
+		}
+		stat, err := os.Stat(template)
+		if err != nil {
+			log.Printf("Could not read %s: %s\n", template, err)
+			continue
+		}
+		present[template] = struct{}{}
+
+		cached, ok := cache.Templates[template]
+		if ok && !stat.ModTime().After(cache.WrittenAt) && stat.ModTime().Equal(cached.ModTime) {
+			reused++
+			continue
+		}
+		if ok {
+			cache.remove(template)
+		}
+		parsed++
+		if updated, ok := parseCachedTemplate(template, stat.ModTime(), cfg); ok {
+			cache.add(template, updated)
+		}
+	}
+	for template := range cache.Templates {
+		if _, ok := present[template]; !ok {
+			cache.remove(template)
+		}
+	}
+	cache.WrittenAt = now
+	if cfg.Verbose {
+		log.Printf("Reused %d cached templates, parsed %d\n", reused, parsed)
+	}
+	return nil
+}
+
+func printCachedAuthorStats(cfg StatsConfig, cachePath string, writer io.Writer) error {
+	cache, err := loadAuthorStatsCache(cachePath)
+	if err != nil {
+		return err
+	}
+	if err := updateAuthorStatsCache(cfg, cache); err != nil {
+		return err
+	}
+	if err := saveAuthorStatsCache(cachePath, cache); err != nil {
+		return err
+	}
+
+	type cachedAuthor struct {
+		Author   string    `json:"author"`
+		Count    int       `json:"count"`
+		CVECount int       `json:"cve_count"`
+		TopTag   string    `json:"top_tag"`
+		LastSeen time.Time `json:"last_seen"`
+	}
+	authors := make([]cachedAuthor, 0, len(cache.Authors))
+	for author, stats := range cache.Authors {
+		authors = append(authors, cachedAuthor{Author: author, Count: stats.Count, CVECount: stats.CVECount, TopTag: topKey(stats.Tags), LastSeen: stats.LastSeen})
+	}
+	sort.Slice(authors, func(i, j int) bool {
+		if authors[i].Count != authors[j].Count {
+			return authors[i].Count > authors[j].Count
+		}
+		return authors[i].Author < authors[j].Author
+	})
+	if *count > 0 && len(authors) > *count {
+		authors = authors[:*count]
+	}
+
+	rows := make([][]string, 0, len(authors))
+	for _, author := range authors {
+		rows = append(rows, []string{author.Author, strconv.Itoa(author.Count), strconv.Itoa(author.CVECount), author.TopTag, author.LastSeen.Format("2006-01-02")})
+	}
+	writeReport(writer, authors, []string{"Author", "Templates", "CVE", "Top Tag", "Last Seen"}, rows)
+	return nil
+}
@@ -0,0 +1,473 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+	"github.com/projectdiscovery/templates-stats/pkg/templatestats"
+	sliceutil "github.com/projectdiscovery/utils/slice"
+)
+
+// recentWindow is the period a template is considered recently added in
+const recentWindow = 30 * 24 * time.Hour
+
+type AuthorGrowth struct {
+	Author                string  `json:"author"`
+	Total                 int     `json:"total"`
+	FirstContributionDate string  `json:"first_contribution_date"`
+	Recent30d             int     `json:"recent_30d"`
+	GrowthRate            float64 `json:"growth_rate"`
+}
+
+// templateAuthors returns the normalized authors of a template
+func templateAuthors(record templateRecord) []string {
+	author, ok := record.Info["author"]
+	if !ok {
+		return nil
+	}
+	return templatestats.ExplodeCommaSeparatedField(types.ToString(author))
+}
+
+// computeAuthorGrowth uses the template modification time to find the first
+// contribution of each author and how many templates were added recently.
+func computeAuthorGrowth(records []templateRecord, now time.Time) []AuthorGrowth {
+	growthMap := make(map[string]*AuthorGrowth)
+	firstSeen := make(map[string]time.Time)
+	for _, record := range records {
+		for _, author := range templateAuthors(record) {
+			growth, ok := growthMap[author]
+			if !ok {
+				growth = &AuthorGrowth{Author: author}
+				growthMap[author] = growth
+			}
+			growth.Total++
+			if now.Sub(record.ModTime) <= recentWindow {
+				growth.Recent30d++
+			}
+			if first, ok := firstSeen[author]; !ok || record.ModTime.Before(first) {
+				firstSeen[author] = record.ModTime
+			}
+		}
+	}
+
+	growths := make([]AuthorGrowth, 0, len(growthMap))
+	for author, growth := range growthMap {
+		growth.FirstContributionDate = firstSeen[author].Format("2006-01-02")
+		growth.GrowthRate = float64(growth.Recent30d) / float64(growth.Total)
+		growths = append(growths, *growth)
+	}
+	sort.Slice(growths, func(i, j int) bool {
+		if growths[i].GrowthRate != growths[j].GrowthRate {
+			return growths[i].GrowthRate > growths[j].GrowthRate
+		}
+		return growths[i].Total > growths[j].Total
+	})
+	return growths
+}
+
+func printAuthorGrowth(records []templateRecord, writer io.Writer) {
+	growths := computeAuthorGrowth(records, time.Now())
+	if *count > 0 && len(growths) > *count {
+		growths = growths[:*count]
+	}
+
+	rows := make([][]string, 0, len(growths))
+	for _, growth := range growths {
+		rows = append(rows, []string{growth.Author, strconv.Itoa(growth.Total), growth.FirstContributionDate, strconv.Itoa(growth.Recent30d), fmt.Sprintf("%.2f", growth.GrowthRate)})
+	}
+	writeReport(writer, growths, []string{"Author", "Total", "First Contribution", "Recent 30d", "Growth Rate"}, rows)
+}
+
+type HybridAuthor struct {
+	Author      string `json:"author"`
+	CveCount    int    `json:"cve_count"`
+	NonCveCount int    `json:"non_cve_count"`
+	Total       int    `json:"total"`
+}
+
+// isCveTemplate reports whether a template id is a CVE id
+func isCveTemplate(record templateRecord) bool {
+	return strings.HasPrefix(record.ID, "CVE-")
+}
+
+// computeHybridAuthors returns the authors contributing both CVE and non-CVE templates
+func computeHybridAuthors(records []templateRecord) []HybridAuthor {
+	cveAuthors := make(map[string]int)
+	nonCveAuthors := make(map[string]int)
+	for _, record := range records {
+		for _, author := range templateAuthors(record) {
+			if isCveTemplate(record) {
+				cveAuthors[author]++
+			} else {
+				nonCveAuthors[author]++
+			}
+		}
+	}
+
+	var hybrids []HybridAuthor
+	for author, cveCount := range cveAuthors {
+		nonCveCount, ok := nonCveAuthors[author]
+		if !ok {
+			continue
+		}
+		hybrids = append(hybrids, HybridAuthor{Author: author, CveCount: cveCount, NonCveCount: nonCveCount, Total: cveCount + nonCveCount})
+	}
+	sort.Slice(hybrids, func(i, j int) bool {
+		if hybrids[i].Total != hybrids[j].Total {
+			return hybrids[i].Total > hybrids[j].Total
+		}
+		return hybrids[i].Author < hybrids[j].Author
+	})
+	return hybrids
+}
+
+func printHybridAuthors(records []templateRecord, writer io.Writer) {
+	hybrids := computeHybridAuthors(records)
+	if *count > 0 && len(hybrids) > *count {
+		hybrids = hybrids[:*count]
+	}
+
+	rows := make([][]string, 0, len(hybrids))
+	for _, hybrid := range hybrids {
+		rows = append(rows, []string{hybrid.Author, strconv.Itoa(hybrid.CveCount), strconv.Itoa(hybrid.NonCveCount), strconv.Itoa(hybrid.Total)})
+	}
+	writeReport(writer, hybrids, []string{"Author", "CVE", "Non-CVE", "Total"}, rows)
+}
+
+// noCveAuthorTopTags is the number of tags shown per author by -authors-no-cve
+const noCveAuthorTopTags = 3
+
+type NoCveAuthor struct {
+	Author        string   `json:"author"`
+	TemplateCount int      `json:"template_count"`
+	TopTags       []string `json:"top_tags"`
+}
+
+// computeNoCveAuthors returns the authors which never contributed a CVE template
+func computeNoCveAuthors(records []templateRecord) []NoCveAuthor {
+	cveAuthors := make(map[string]struct{})
+	templateCounts := make(map[string]int)
+	tags := make(map[string]map[string]int)
+	for _, record := range records {
+		for _, author := range templateAuthors(record) {
+			if isCveTemplate(record) {
+				cveAuthors[author] = struct{}{}
+				continue
+			}
+			templateCounts[author]++
+			if tags[author] == nil {
+				tags[author] = make(map[string]int)
+			}
+			for _, tag := range templatestats.TemplateTags(record) {
+				tags[author][tag]++
+			}
+		}
+	}
+
+	var authors []NoCveAuthor
+	for author, templateCount := range templateCounts {
+		if _, ok := cveAuthors[author]; ok {
+			continue
+		}
+		item := NoCveAuthor{Author: author, TemplateCount: templateCount, TopTags: []string{}}
+		for _, pair := range newPairListFromMap(tags[author], noCveAuthorTopTags) {
+			item.TopTags = append(item.TopTags, pair.Key)
+		}
+		authors = append(authors, item)
+	}
+	sort.Slice(authors, func(i, j int) bool {
+		if authors[i].TemplateCount != authors[j].TemplateCount {
+			return authors[i].TemplateCount > authors[j].TemplateCount
+		}
+		return authors[i].Author < authors[j].Author
+	})
+	return authors
+}
+
+func printNoCveAuthors(records []templateRecord, writer io.Writer) {
+	authors := computeNoCveAuthors(records)
+	if *count > 0 && len(authors) > *count {
+		authors = authors[:*count]
+	}
+
+	rows := make([][]string, 0, len(authors))
+	for _, author := range authors {
+		rows = append(rows, []string{author.Author, strconv.Itoa(author.TemplateCount), strings.Join(author.TopTags, ", ")})
+	}
+	writeReport(writer, authors, []string{"Author", "Templates", "Top Tags"}, rows)
+}
+
+// authorOverlapTopPairs is the number of pairs reported by -author-overlap
+const authorOverlapTopPairs = 20
+
+type AuthorOverlap struct {
+	Author1         string  `json:"author1"`
+	Author2         string  `json:"author2"`
+	SharedTemplates int     `json:"shared_templates"`
+	Jaccard         float64 `json:"jaccard"`
+}
+
+// computeAuthorOverlap returns the jaccard similarity of the template sets
+// of every pair of authors sharing at least one template.
+func computeAuthorOverlap(records []templateRecord) []AuthorOverlap {
+	templateCounts := make(map[string]int)
+	shared := make(map[[2]string]int)
+	for _, record := range records {
+		authors := sliceutil.Dedupe(templateAuthors(record))
+		sort.Strings(authors)
+		for i, author := range authors {
+			templateCounts[author]++
+			for _, other := range authors[i+1:] {
+				shared[[2]string{author, other}]++
+			}
+		}
+	}
+
+	overlaps := make([]AuthorOverlap, 0, len(shared))
+	for pair, sharedCount := range shared {
+		union := templateCounts[pair[0]] + templateCounts[pair[1]] - sharedCount
+		overlaps = append(overlaps, AuthorOverlap{
+			Author1:         pair[0],
+			Author2:         pair[1],
+			SharedTemplates: sharedCount,
+			Jaccard:         float64(sharedCount) / float64(union),
+		})
+	}
+	sort.Slice(overlaps, func(i, j int) bool {
+		if overlaps[i].Jaccard != overlaps[j].Jaccard {
+			return overlaps[i].Jaccard > overlaps[j].Jaccard
+		}
+		return overlaps[i].SharedTemplates > overlaps[j].SharedTemplates
+	})
+	return overlaps
+}
+
+func printAuthorOverlap(records []templateRecord, writer io.Writer) {
+	overlaps := computeAuthorOverlap(records)
+	limit := authorOverlapTopPairs
+	if *count > 0 {
+		limit = *count
+	}
+	if len(overlaps) > limit {
+		overlaps = overlaps[:limit]
+	}
+
+	rows := make([][]string, 0, len(overlaps))
+	for _, overlap := range overlaps {
+		rows = append(rows, []string{overlap.Author1, overlap.Author2, strconv.Itoa(overlap.SharedTemplates), fmt.Sprintf("%.3f", overlap.Jaccard)})
+	}
+	writeReport(writer, overlaps, []string{"Author", "Author", "Shared", "Jaccard"}, rows)
+}
+
+// authorHistogramBuckets are the template count ranges of the author histogram
+var authorHistogramBuckets = []struct {
+	Name string
+	Min  int
+	Max  int
+}{
+	{Name: "1", Min: 1, Max: 1},
+	{Name: "2-5", Min: 2, Max: 5},
+	{Name: "6-20", Min: 6, Max: 20},
+	{Name: "21-100", Min: 21, Max: 100},
+	{Name: "101+", Min: 101},
+}
+
+type AuthorTemplateRatio struct {
+	TotalTemplates   int      `json:"total_templates"`
+	UniqueAuthors    int      `json:"unique_authors"`
+	AverageTemplates float64  `json:"average_templates"`
+	Histogram        PairList `json:"histogram"`
+}
+
+// computeAuthorTemplateRatio returns the average number of templates per
+// author and how many authors fall in each template count bucket.
+func computeAuthorTemplateRatio(authorMap map[string]int, totalTemplates int) *AuthorTemplateRatio {
+	ratio := &AuthorTemplateRatio{TotalTemplates: totalTemplates}
+	ratio.Histogram = make(PairList, len(authorHistogramBuckets))
+	for i, bucket := range authorHistogramBuckets {
+		ratio.Histogram[i].Key = bucket.Name
+	}
+	for author, templates := range authorMap {
+		if author == "" {
+			continue
+		}
+		ratio.UniqueAuthors++
+		for i, bucket := range authorHistogramBuckets {
+			if templates >= bucket.Min && (bucket.Max == 0 || templates <= bucket.Max) {
+				ratio.Histogram[i].Value++
+				break
+			}
+		}
+	}
+	if ratio.UniqueAuthors > 0 {
+		ratio.AverageTemplates = float64(totalTemplates) / float64(ratio.UniqueAuthors)
+	}
+	return ratio
+}
+
+func printAuthorTemplateRatio(authorMap map[string]int, totalTemplates int, writer io.Writer) {
+	ratio := computeAuthorTemplateRatio(authorMap, totalTemplates)
+	if !*jsonOutput {
+		fmt.Fprintf(writer, "Average templates per author: %.2f (%d templates / %d authors)\n\n", ratio.AverageTemplates, ratio.TotalTemplates, ratio.UniqueAuthors)
+	}
+
+	rows := make([][]string, 0, len(ratio.Histogram))
+	for _, bucket := range ratio.Histogram {
+		rows = append(rows, []string{bucket.Key, strconv.Itoa(bucket.Value)})
+	}
+	writeReport(writer, ratio, []string{"Templates", "Authors"}, rows)
+}
+
+// severityScores are the numeric scores of the severities, unknown values score 0
+var severityScores = map[string]int{"info": 1, "low": 2, "medium": 3, "high": 4, "critical": 5}
+
+type AuthorSeverity struct {
+	Author           string  `json:"author"`
+	AvgSeverityScore float64 `json:"avg_severity_score"`
+	DominantSeverity string  `json:"dominant_severity"`
+	TemplateCount    int     `json:"template_count"`
+}
+
+func computeAuthorSeverity(records []templateRecord) []AuthorSeverity {
+	scores := make(map[string]int)
+	severities := make(map[string]map[string]int)
+	for _, record := range records {
+		severity := strings.ToLower(types.ToString(record.Info["severity"]))
+		for _, author := range templateAuthors(record) {
+			if _, ok := severities[author]; !ok {
+				severities[author] = make(map[string]int)
+			}
+			severities[author][severity]++
+			scores[author] += severityScores[severity]
+		}
+	}
+
+	result := make([]AuthorSeverity, 0, len(severities))
+	for author, counts := range severities {
+		item := AuthorSeverity{Author: author}
+		for severity, count := range counts {
+			item.TemplateCount += count
+			if dominant := counts[item.DominantSeverity]; item.DominantSeverity == "" || count > dominant || (count == dominant && severityScores[severity] > severityScores[item.DominantSeverity]) {
+				item.DominantSeverity = severity
+			}
+		}
+		item.AvgSeverityScore = float64(scores[author]) / float64(item.TemplateCount)
+		result = append(result, item)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].AvgSeverityScore != result[j].AvgSeverityScore {
+			return result[i].AvgSeverityScore > result[j].AvgSeverityScore
+		}
+		return result[i].TemplateCount > result[j].TemplateCount
+	})
+	return result
+}
+
+func printAuthorSeverity(records []templateRecord, writer io.Writer) {
+	result := computeAuthorSeverity(records)
+	if *count > 0 && len(result) > *count {
+		result = result[:*count]
+	}
+
+	rows := make([][]string, 0, len(result))
+	for _, item := range result {
+		rows = append(rows, []string{item.Author, fmt.Sprintf("%.2f", item.AvgSeverityScore), item.DominantSeverity, strconv.Itoa(item.TemplateCount)})
+	}
+	writeReport(writer, result, []string{"Author", "Avg Severity Score", "Dominant Severity", "Templates"}, rows)
+}
+
+type AuthorReport struct {
+	Name        string  `json:"name"`
+	Count       int     `json:"count"`
+	Rank        int     `json:"rank"`
+	PctOfTotal  float64 `json:"pct_of_total"`
+	CveCount    int     `json:"cve_count"`
+	NonCveCount int     `json:"non_cve_count"`
+	TopTag      string  `json:"top_tag"`
+	TopSeverity string  `json:"top_severity"`
+}
+
+// topKey returns the key with the highest count of a map
+func topKey(counts map[string]int) string {
+	pairs := newPairListFromMap(counts, 1)
+	if len(pairs) == 0 {
+		return ""
+	}
+	return pairs[0].Key
+}
+
+// computeAuthorReports returns the enriched author leaderboard. When n is
+// non-zero only the n first ranked authors are kept.
+func computeAuthorReports(records []templateRecord, n int) []AuthorReport {
+	reports := make(map[string]*AuthorReport)
+	tags := make(map[string]map[string]int)
+	severities := make(map[string]map[string]int)
+	for _, record := range records {
+		severity := strings.ToLower(types.ToString(record.Info["severity"]))
+		for _, author := range templateAuthors(record) {
+			report, ok := reports[author]
+			if !ok {
+				report = &AuthorReport{Name: author}
+				reports[author] = report
+				tags[author] = make(map[string]int)
+				severities[author] = make(map[string]int)
+			}
+			report.Count++
+			if isCveTemplate(record) {
+				report.CveCount++
+			} else {
+				report.NonCveCount++
+			}
+			for _, tag := range templatestats.TemplateTags(record) {
+				tags[author][tag]++
+			}
+			if severity != "" {
+				severities[author][severity]++
+			}
+		}
+	}
+
+	result := make([]AuthorReport, 0, len(reports))
+	for author, report := range reports {
+		report.PctOfTotal = float64(report.Count) * 100 / float64(len(records))
+		report.TopTag = topKey(tags[author])
+		report.TopSeverity = topKey(severities[author])
+		result = append(result, *report)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Name < result[j].Name
+	})
+	for i := range result {
+		result[i].Rank = i + 1
+	}
+	if n > 0 && len(result) > n {
+		result = result[:n]
+	}
+	return result
+}
+
+func writeAuthorReport(path string, records []templateRecord, n int) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "could not create author report file")
+	}
+	defer f.Close()
+
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(computeAuthorReports(records, n)); err != nil {
+		return errors.Wrap(err, "could not encode author report")
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+	"github.com/projectdiscovery/templates-stats/pkg/templatestats"
+)
+
+// typeAuthorsTop is the default number of authors shown per template type
+const typeAuthorsTop = 5
+
+type TypeAuthors struct {
+	Type    string   `json:"type"`
+	Authors PairList `json:"authors"`
+}
+
+// templateTypeName returns the display name of a request type key, the
+// legacy requests key is reported as http.
+func templateTypeName(key string) string {
+	if key == "requests" {
+		return "http"
+	}
+	return key
+}
+
+// computeTypeAuthors returns the top n authors of every template type
+func computeTypeAuthors(records []templateRecord, n int) []TypeAuthors {
+	counts := make(map[string]map[string]int)
+	for _, record := range records {
+		seen := make(map[string]struct{})
+		for _, key := range templateRequestTypes(record) {
+			name := templateTypeName(key)
+			if _, ok := seen[name]; ok {
+				continue
+			}
+			seen[name] = struct{}{}
+			if counts[name] == nil {
+				counts[name] = make(map[string]int)
+			}
+			for _, author := range templateAuthors(record) {
+				counts[name][author]++
+			}
+		}
+	}
+
+	result := make([]TypeAuthors, 0, len(counts))
+	for name, authors := range counts {
+		result = append(result, TypeAuthors{Type: name, Authors: newPairListFromMap(authors, n)})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Type < result[j].Type })
+	return result
+}
+
+func printTypeAuthors(records []templateRecord, writer io.Writer) {
+	n := *count
+	if n <= 0 {
+		n = typeAuthorsTop
+	}
+	typeAuthors := computeTypeAuthors(records, n)
+
+	header := []string{"Type"}
+	for i := 1; i <= n; i++ {
+		header = append(header, fmt.Sprintf("#%d", i))
+	}
+	rows := make([][]string, 0, len(typeAuthors))
+	for _, item := range typeAuthors {
+		row := make([]string, n+1)
+		row[0] = item.Type
+		for i, pair := range item.Authors {
+			row[i+1] = fmt.Sprintf("%s (%d)", pair.Key, pair.Value)
+		}
+		rows = append(rows, row)
+	}
+	writeReport(writer, typeAuthors, header, rows)
+}
+
+// typeTagsTop is the default number of tags shown per template type
+const typeTagsTop = 10
+
+type TypeTags struct {
+	Type string   `json:"type"`
+	Tags PairList `json:"tags"`
+}
+
+// computeTypeTags returns the top n tags of every template type
+func computeTypeTags(typeTags map[string]map[string]int, n int) []TypeTags {
+	result := make([]TypeTags, 0, len(typeTags))
+	for name, tags := range typeTags {
+		result = append(result, TypeTags{Type: name, Tags: newPairListFromMap(tags, n)})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Type < result[j].Type })
+	return result
+}
+
+func printTypeTags(typeTags map[string]map[string]int, writer io.Writer) {
+	n := *count
+	if n <= 0 {
+		n = typeTagsTop
+	}
+	items := computeTypeTags(typeTags, n)
+
+	header := []string{"Type"}
+	for i := 1; i <= n; i++ {
+		header = append(header, fmt.Sprintf("#%d", i))
+	}
+	rows := make([][]string, 0, len(items))
+	for _, item := range items {
+		row := make([]string, n+1)
+		row[0] = item.Type
+		for i, pair := range item.Tags {
+			row[i+1] = fmt.Sprintf("%s (%d)", pair.Key, pair.Value)
+		}
+		rows = append(rows, row)
+	}
+	writeReport(writer, items, header, rows)
+}
+
+// networkPortProtocols maps well known ports to the protocol served on them
+var networkPortProtocols = map[string]string{
+	"21":    "ftp",
+	"22":    "ssh",
+	"23":    "telnet",
+	"25":    "smtp",
+	"110":   "pop3",
+	"143":   "imap",
+	"389":   "ldap",
+	"445":   "smb",
+	"1433":  "mssql",
+	"1521":  "oracle",
+	"2181":  "zookeeper",
+	"2375":  "docker",
+	"3306":  "mysql",
+	"3389":  "rdp",
+	"5432":  "postgres",
+	"5672":  "amqp",
+	"5900":  "vnc",
+	"6379":  "redis",
+	"9092":  "kafka",
+	"9200":  "elasticsearch",
+	"11211": "memcached",
+	"27017": "mongodb",
+}
+
+// templateNetworkPorts returns the ports targeted by the host and port
+// fields of the network request blocks of a template.
+func templateNetworkPorts(data map[string]interface{}) []string {
+	var ports []string
+	for _, key := range []string{"network", "tcp"} {
+		items, ok := data[key].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, item := range items {
+			block, ok := item.(map[interface{}]interface{})
+			if !ok {
+				continue
+			}
+			if port := strings.TrimSpace(types.ToString(block["port"])); port != "" {
+				ports = append(ports, templatestats.ExplodeCommaSeparatedField(port)...)
+			}
+			hosts, _ := block["host"].([]interface{})
+			for _, host := range hosts {
+				value := types.ToString(host)
+				index := strings.LastIndex(value, ":")
+				if index == -1 {
+					continue
+				}
+				if port := value[index+1:]; port != "" {
+					if _, err := strconv.Atoi(port); err == nil {
+						ports = append(ports, port)
+					}
+				}
+			}
+		}
+	}
+	return ports
+}
+
+// computeNetworkProtocols counts the templates targeting every network
+// protocol, ports without a known protocol are reported as tcp/<port>.
+func computeNetworkProtocols(records []templateRecord) map[string]int {
+	protocols := make(map[string]int)
+	for _, record := range records {
+		seen := make(map[string]struct{})
+		for _, port := range templateNetworkPorts(record.Data) {
+			protocol, ok := networkPortProtocols[port]
+			if !ok {
+				protocol = "tcp/" + port
+			}
+			if _, ok := seen[protocol]; ok {
+				continue
+			}
+			seen[protocol] = struct{}{}
+			protocols[protocol]++
+		}
+	}
+	return protocols
+}
+
+func printNetworkProtocols(records []templateRecord, writer io.Writer) {
+	protocols := newPairListFromMap(computeNetworkProtocols(records), *count)
+
+	rows := make([][]string, 0, len(protocols))
+	for _, pair := range protocols {
+		rows = append(rows, []string{pair.Key, strconv.Itoa(pair.Value)})
+	}
+	writeReport(writer, protocols, []string{"Protocol", "Count"}, rows)
+}
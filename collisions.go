@@ -0,0 +1,176 @@
+package main
+
+import (
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+)
+
+// matcherValueKeys are the matcher fields compared to detect collisions
+var matcherValueKeys = []string{"status", "words", "regex", "dsl", "binary", "size"}
+
+type PotentialCollision struct {
+	Path1         string `json:"path1"`
+	Path2         string `json:"path2"`
+	SharedPattern string `json:"shared_pattern"`
+}
+
+// httpSignature is the method, path prefix and matchers of a http request
+type httpSignature struct {
+	Method     string
+	PathPrefix string
+	Matchers   string
+}
+
+// stringList returns the string items of a yaml list or scalar value
+func stringList(value interface{}) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, types.ToString(item))
+		}
+		return items
+	}
+	return []string{types.ToString(value)}
+}
+
+// matchersKey returns a canonical representation of the matchers of a block
+func matchersKey(block map[interface{}]interface{}) string {
+	items, _ := block["matchers"].([]interface{})
+	var keys []string
+	for _, item := range items {
+		matcher, ok := item.(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		var values []string
+		for _, key := range matcherValueKeys {
+			values = append(values, stringList(matcher[key])...)
+		}
+		sort.Strings(values)
+		keys = append(keys, strings.ToLower(types.ToString(matcher["type"]))+":"+strings.Join(values, ","))
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, " ")
+}
+
+// requestPathPrefix returns the first segment of a request path without the
+// base url variable, e.g. /struts for {{BaseURL}}/struts/upload?x=1.
+func requestPathPrefix(path string) string {
+	path = strings.TrimSpace(path)
+	for _, variable := range []string{"{{BaseURL}}", "{{RootURL}}", "{{Hostname}}"} {
+		path = strings.TrimPrefix(path, variable)
+	}
+	if index := strings.Index(path, "?"); index != -1 {
+		path = path[:index]
+	}
+	path = strings.TrimPrefix(path, "/")
+	if index := strings.Index(path, "/"); index != -1 {
+		path = path[:index]
+	}
+	return "/" + path
+}
+
+// httpSignatures returns the signature of every http request of a template
+func httpSignatures(record templateRecord) []httpSignature {
+	var signatures []httpSignature
+	for _, key := range []string{"requests", "http"} {
+		blocks, ok := record.Data[key].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, item := range blocks {
+			block, ok := item.(map[interface{}]interface{})
+			if !ok {
+				continue
+			}
+			matchers := matchersKey(block)
+			if matchers == "" {
+				continue
+			}
+			method := strings.ToUpper(types.ToString(block["method"]))
+			if method == "" {
+				method = "GET"
+			}
+			for _, path := range stringList(block["path"]) {
+				signatures = append(signatures, httpSignature{Method: method, PathPrefix: requestPathPrefix(path), Matchers: matchers})
+			}
+			for _, raw := range stringList(block["raw"]) {
+				fields := strings.Fields(strings.TrimSpace(raw))
+				if len(fields) < 2 {
+					continue
+				}
+				signatures = append(signatures, httpSignature{Method: strings.ToUpper(fields[0]), PathPrefix: requestPathPrefix(fields[1]), Matchers: matchers})
+			}
+		}
+	}
+	return signatures
+}
+
+// sharedPattern returns the first signature shared by two templates
+func sharedPattern(first, second []httpSignature) (string, bool) {
+	for _, a := range first {
+		for _, b := range second {
+			if a == b {
+				return a.Method + " " + a.PathPrefix + " " + a.Matchers, true
+			}
+		}
+	}
+	return "", false
+}
+
+// findPotentialCollisions compares the http requests of the templates of
+// every directory and reports the pairs which may fire on the same target.
+func findPotentialCollisions(records []templateRecord) []PotentialCollision {
+	type signedTemplate struct {
+		path       string
+		signatures []httpSignature
+	}
+	directories := make(map[string][]signedTemplate)
+	for _, record := range records {
+		if signatures := httpSignatures(record); len(signatures) > 0 {
+			dir := filepath.Dir(record.Path)
+			directories[dir] = append(directories[dir], signedTemplate{path: record.Path, signatures: signatures})
+		}
+	}
+
+	var collisions []PotentialCollision
+	for _, templates := range directories {
+		for i, first := range templates {
+			for _, second := range templates[i+1:] {
+				pattern, ok := sharedPattern(first.signatures, second.signatures)
+				if !ok {
+					continue
+				}
+				path1, path2 := first.path, second.path
+				if path2 < path1 {
+					path1, path2 = path2, path1
+				}
+				collisions = append(collisions, PotentialCollision{Path1: path1, Path2: path2, SharedPattern: pattern})
+			}
+		}
+	}
+	sort.Slice(collisions, func(i, j int) bool {
+		if collisions[i].Path1 != collisions[j].Path1 {
+			return collisions[i].Path1 < collisions[j].Path1
+		}
+		return collisions[i].Path2 < collisions[j].Path2
+	})
+	return collisions
+}
+
+func printPotentialCollisions(records []templateRecord, writer io.Writer) {
+	collisions := findPotentialCollisions(records)
+
+	rows := make([][]string, 0, len(collisions))
+	for _, collision := range collisions {
+		rows = append(rows, []string{collision.Path1, collision.Path2, collision.SharedPattern})
+	}
+	writeReport(writer, collisions, []string{"Path 1", "Path 2", "Shared Pattern"}, rows)
+}
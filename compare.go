@@ -0,0 +1,306 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type SeverityChange struct {
+	Severity      string  `json:"severity"`
+	BaselineCount int     `json:"baseline_count"`
+	CurrentCount  int     `json:"current_count"`
+	BaselinePct   float64 `json:"baseline_pct"`
+	CurrentPct    float64 `json:"current_pct"`
+	DeltaPct      float64 `json:"delta_pct"`
+}
+
+// loadOutput reads an output previously written with -json
+func loadOutput(path string) (*Output, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open output file")
+	}
+	defer f.Close()
+
+	output := &Output{}
+	if err := json.NewDecoder(f).Decode(output); err != nil {
+		return nil, errors.Wrap(err, "could not decode output file")
+	}
+	return output, nil
+}
+
+// percentages converts counts into their percentage of the total count
+func percentages(counts map[string]int) map[string]float64 {
+	total := 0
+	for _, value := range counts {
+		total += value
+	}
+	pcts := make(map[string]float64, len(counts))
+	for key, value := range counts {
+		if total > 0 {
+			pcts[key] = float64(value) * 100 / float64(total)
+		}
+	}
+	return pcts
+}
+
+// severityOrder are the known severities from unknown to critical
+var severityOrder = []string{"unknown", "info", "low", "medium", "high", "critical"}
+
+// severityRank orders known severities from unknown to critical
+func severityRank(severity string) int {
+	for i, valid := range severityOrder {
+		if severity == valid {
+			return i
+		}
+	}
+	return -1
+}
+
+func computeSeverityChanges(baseline *Output, severityMap map[string]int) []SeverityChange {
+	baselineMap := make(map[string]int, len(baseline.Severity))
+	for _, pair := range baseline.Severity {
+		baselineMap[pair.Key] = pair.Value
+	}
+	baselinePcts := percentages(baselineMap)
+	currentPcts := percentages(severityMap)
+
+	levels := make(map[string]struct{})
+	for severity := range baselineMap {
+		levels[severity] = struct{}{}
+	}
+	for severity := range severityMap {
+		levels[severity] = struct{}{}
+	}
+
+	changes := make([]SeverityChange, 0, len(levels))
+	for severity := range levels {
+		changes = append(changes, SeverityChange{
+			Severity:      severity,
+			BaselineCount: baselineMap[severity],
+			CurrentCount:  severityMap[severity],
+			BaselinePct:   baselinePcts[severity],
+			CurrentPct:    currentPcts[severity],
+			DeltaPct:      currentPcts[severity] - baselinePcts[severity],
+		})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if first, second := severityRank(changes[i].Severity), severityRank(changes[j].Severity); first != second {
+			return first > second
+		}
+		return changes[i].Severity < changes[j].Severity
+	})
+	return changes
+}
+
+func printSeverityChanges(baselineFile string, severityMap map[string]int, writer io.Writer) error {
+	baseline, err := loadOutput(baselineFile)
+	if err != nil {
+		return err
+	}
+	changes := computeSeverityChanges(baseline, severityMap)
+
+	rows := make([][]string, 0, len(changes))
+	for _, change := range changes {
+		rows = append(rows, []string{
+			change.Severity,
+			strconv.Itoa(change.BaselineCount),
+			strconv.Itoa(change.CurrentCount),
+			fmt.Sprintf("%.2f%%", change.BaselinePct),
+			fmt.Sprintf("%.2f%%", change.CurrentPct),
+			fmt.Sprintf("%+.2f%%", change.DeltaPct),
+		})
+	}
+	writeReport(writer, changes, []string{"Severity", "Baseline", "Current", "Baseline %", "Current %", "Delta"}, rows)
+	return nil
+}
+
+type AuthorRankChange struct {
+	Author      string  `json:"author"`
+	RankInFile1 float64 `json:"rank_in_file1"`
+	RankInFile2 float64 `json:"rank_in_file2"`
+}
+
+type AuthorRankingReport struct {
+	Authors     int                `json:"authors"`
+	Correlation float64            `json:"correlation"`
+	PValue      float64            `json:"p_value"`
+	Changes     []AuthorRankChange `json:"changes"`
+}
+
+// computeAuthorRankingReport compares the author rankings of two outputs
+// using the spearman rank correlation of the authors present in both.
+func computeAuthorRankingReport(first, second *Output) *AuthorRankingReport {
+	secondCounts := make(map[string]int, len(second.Authors))
+	for _, pair := range second.Authors {
+		secondCounts[pair.Key] = pair.Value
+	}
+	var (
+		authors      []string
+		firstValues  []float64
+		secondValues []float64
+	)
+	for _, pair := range first.Authors {
+		value, ok := secondCounts[pair.Key]
+		if !ok || pair.Key == "" {
+			continue
+		}
+		authors = append(authors, pair.Key)
+		firstValues = append(firstValues, float64(pair.Value))
+		secondValues = append(secondValues, float64(value))
+	}
+	firstRanks, secondRanks := fractionalRanks(firstValues), fractionalRanks(secondValues)
+
+	report := &AuthorRankingReport{Authors: len(authors), Changes: []AuthorRankChange{}}
+	report.Correlation = pearson(firstRanks, secondRanks)
+	report.PValue = spearmanPValue(report.Correlation, len(authors))
+	for i, author := range authors {
+		if firstRanks[i] != secondRanks[i] {
+			report.Changes = append(report.Changes, AuthorRankChange{Author: author, RankInFile1: firstRanks[i], RankInFile2: secondRanks[i]})
+		}
+	}
+	sort.Slice(report.Changes, func(i, j int) bool { return report.Changes[i].RankInFile1 < report.Changes[j].RankInFile1 })
+	return report
+}
+
+func printAuthorRankingReport(firstFile, secondFile string, writer io.Writer) error {
+	first, err := loadOutput(firstFile)
+	if err != nil {
+		return err
+	}
+	second, err := loadOutput(secondFile)
+	if err != nil {
+		return err
+	}
+	report := computeAuthorRankingReport(first, second)
+	if *jsonOutput {
+		if err := json.NewEncoder(writer).Encode(report); err != nil {
+			log.Fatalf("Could not encode json: %s\n", err)
+		}
+		return nil
+	}
+
+	fmt.Fprintf(writer, "Spearman correlation: %.4f (p=%.4f, %d authors)\n", report.Correlation, report.PValue, report.Authors)
+	rows := make([][]string, 0, len(report.Changes))
+	for _, change := range report.Changes {
+		rows = append(rows, []string{change.Author, strconv.FormatFloat(change.RankInFile1, 'f', -1, 64), strconv.FormatFloat(change.RankInFile2, 'f', -1, 64)})
+	}
+	renderTable(writer, []string{"Author", "Rank In File 1", "Rank In File 2"}, rows)
+	return nil
+}
+
+type AuthorPeriodComparison struct {
+	Author       string `json:"author"`
+	Period1Count int    `json:"period1_count"`
+	Period2Count int    `json:"period2_count"`
+	Delta        int    `json:"delta"`
+	Trend        string `json:"trend"`
+}
+
+// datePeriod is an inclusive range of days
+type datePeriod struct {
+	start, end time.Time
+}
+
+// parseDatePeriod parses a YYYY-MM-DD:YYYY-MM-DD period, both days included
+func parseDatePeriod(value string) (datePeriod, error) {
+	parts := strings.SplitN(value, ":", 2)
+	if len(parts) != 2 {
+		return datePeriod{}, errors.Errorf("invalid period %q, expected YYYY-MM-DD:YYYY-MM-DD", value)
+	}
+	start, err := time.Parse("2006-01-02", parts[0])
+	if err != nil {
+		return datePeriod{}, errors.Wrap(err, "could not parse period start")
+	}
+	end, err := time.Parse("2006-01-02", parts[1])
+	if err != nil {
+		return datePeriod{}, errors.Wrap(err, "could not parse period end")
+	}
+	if end.Before(start) {
+		return datePeriod{}, errors.Errorf("period %q ends before it starts", value)
+	}
+	return datePeriod{start: start, end: end.AddDate(0, 0, 1)}, nil
+}
+
+func (p datePeriod) contains(t time.Time) bool {
+	return !t.Before(p.start) && t.Before(p.end)
+}
+
+// trendArrow returns the direction of delta
+func trendArrow(delta int) string {
+	switch {
+	case delta > 0:
+		return "↑"
+	case delta < 0:
+		return "↓"
+	default:
+		return "="
+	}
+}
+
+// computeAuthorPeriodComparison counts the templates of every author modified
+// within each period, for the authors active in either of them.
+func computeAuthorPeriodComparison(records []templateRecord, first, second datePeriod) []AuthorPeriodComparison {
+	authors := make(map[string]*AuthorPeriodComparison)
+	for _, record := range records {
+		inFirst, inSecond := first.contains(record.ModTime), second.contains(record.ModTime)
+		if !inFirst && !inSecond {
+			continue
+		}
+		for _, author := range templateAuthors(record) {
+			item, ok := authors[author]
+			if !ok {
+				item = &AuthorPeriodComparison{Author: author}
+				authors[author] = item
+			}
+			if inFirst {
+				item.Period1Count++
+			}
+			if inSecond {
+				item.Period2Count++
+			}
+		}
+	}
+
+	comparison := make([]AuthorPeriodComparison, 0, len(authors))
+	for _, item := range authors {
+		item.Delta = item.Period2Count - item.Period1Count
+		item.Trend = trendArrow(item.Delta)
+		comparison = append(comparison, *item)
+	}
+	sort.Slice(comparison, func(i, j int) bool {
+		if comparison[i].Delta != comparison[j].Delta {
+			return comparison[i].Delta > comparison[j].Delta
+		}
+		return comparison[i].Author < comparison[j].Author
+	})
+	return comparison
+}
+
+func printAuthorPeriodComparison(records []templateRecord, firstPeriod, secondPeriod string, writer io.Writer) error {
+	first, err := parseDatePeriod(firstPeriod)
+	if err != nil {
+		return err
+	}
+	second, err := parseDatePeriod(secondPeriod)
+	if err != nil {
+		return err
+	}
+	comparison := computeAuthorPeriodComparison(records, first, second)
+
+	rows := make([][]string, 0, len(comparison))
+	for _, item := range comparison {
+		rows = append(rows, []string{item.Author, strconv.Itoa(item.Period1Count), strconv.Itoa(item.Period2Count), strconv.Itoa(item.Delta), item.Trend})
+	}
+	writeReport(writer, comparison, []string{"Author", firstPeriod, secondPeriod, "Delta", "Trend"}, rows)
+	return nil
+}
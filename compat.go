@@ -0,0 +1,157 @@
+package main
+
+import (
+	_ "embed"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+	"gopkg.in/yaml.v2"
+)
+
+//go:embed deprecations.yaml
+var deprecationsData []byte
+
+// deprecation is a top level key deprecated in a nuclei version
+type deprecation struct {
+	Key        string `yaml:"key"`
+	ReplacedBy string `yaml:"replaced-by"`
+	Version    string `yaml:"version"`
+}
+
+type DeprecationWarning struct {
+	Path          string `json:"path"`
+	DeprecatedKey string `json:"deprecated_key"`
+	ReplacedBy    string `json:"replaced_by"`
+	// Version is the nuclei version which deprecated the key
+	Version string `json:"version"`
+}
+
+func loadDeprecations() ([]deprecation, error) {
+	var deprecations []deprecation
+	if err := yaml.Unmarshal(deprecationsData, &deprecations); err != nil {
+		return nil, err
+	}
+	return deprecations, nil
+}
+
+func findDeprecationWarnings(records []templateRecord, deprecations []deprecation) []DeprecationWarning {
+	var warnings []DeprecationWarning
+	for _, record := range records {
+		for _, deprecated := range deprecations {
+			if _, ok := record.Data[deprecated.Key]; ok {
+				warnings = append(warnings, DeprecationWarning{Path: record.Path, DeprecatedKey: deprecated.Key, ReplacedBy: deprecated.ReplacedBy, Version: deprecated.Version})
+			}
+		}
+	}
+	sort.SliceStable(warnings, func(i, j int) bool { return warnings[i].Path < warnings[j].Path })
+	return warnings
+}
+
+func printDeprecationWarnings(records []templateRecord, writer io.Writer) error {
+	deprecations, err := loadDeprecations()
+	if err != nil {
+		return err
+	}
+	warnings := findDeprecationWarnings(records, deprecations)
+
+	rows := make([][]string, 0, len(warnings))
+	for _, warning := range warnings {
+		rows = append(rows, []string{warning.Path, warning.DeprecatedKey, warning.ReplacedBy, warning.Version})
+	}
+	writeReport(writer, warnings, []string{"Path", "Deprecated Key", "Replaced By", "Version"}, rows)
+	return nil
+}
+
+type FormatVersionViolation struct {
+	Path          string `json:"path"`
+	FormatVersion string `json:"format_version,omitempty"`
+	Reason        string `json:"reason"`
+}
+
+// compareVersions compares two dotted numeric versions, missing components
+// count as zero.
+func compareVersions(first, second string) (int, error) {
+	firstParts := strings.Split(strings.TrimPrefix(first, "v"), ".")
+	secondParts := strings.Split(strings.TrimPrefix(second, "v"), ".")
+	for i := 0; i < len(firstParts) || i < len(secondParts); i++ {
+		var a, b int
+		var err error
+		if i < len(firstParts) {
+			if a, err = strconv.Atoi(firstParts[i]); err != nil {
+				return 0, errors.Errorf("invalid version %q", first)
+			}
+		}
+		if i < len(secondParts) {
+			if b, err = strconv.Atoi(secondParts[i]); err != nil {
+				return 0, errors.Errorf("invalid version %q", second)
+			}
+		}
+		if a != b {
+			if a < b {
+				return -1, nil
+			}
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// findFormatVersionViolations returns the templates whose format-version is
+// outside of [min, max]. Templates without a format-version are reported
+// when they use a deprecated key instead.
+func findFormatVersionViolations(records []templateRecord, deprecations []deprecation, min, max string) []FormatVersionViolation {
+	var violations []FormatVersionViolation
+	for _, record := range records {
+		value, ok := record.Data["format-version"]
+		if !ok {
+			for _, deprecated := range deprecations {
+				if _, ok := record.Data[deprecated.Key]; ok {
+					violations = append(violations, FormatVersionViolation{Path: record.Path, Reason: "no format-version and deprecated key " + deprecated.Key})
+					break
+				}
+			}
+			continue
+		}
+		version := strings.TrimSpace(types.ToString(value))
+		lower, err := compareVersions(version, min)
+		if err != nil {
+			violations = append(violations, FormatVersionViolation{Path: record.Path, FormatVersion: version, Reason: "invalid format-version"})
+			continue
+		}
+		upper, _ := compareVersions(version, max)
+		if lower < 0 || upper > 0 {
+			violations = append(violations, FormatVersionViolation{Path: record.Path, FormatVersion: version, Reason: "outside of " + min + " - " + max})
+		}
+	}
+	sort.Slice(violations, func(i, j int) bool { return violations[i].Path < violations[j].Path })
+	return violations
+}
+
+// printFormatVersionViolations reports the templates outside of versionRange,
+// an inclusive MIN:MAX range such as 1.0:2.1 where both bounds are required.
+func printFormatVersionViolations(records []templateRecord, versionRange string, writer io.Writer) error {
+	parts := strings.SplitN(versionRange, ":", 2)
+	if len(parts) != 2 {
+		return errors.Errorf("invalid format version range %q, expected MIN:MAX such as 1.0:2.1", versionRange)
+	}
+	min, max := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+	if _, err := compareVersions(min, max); err != nil {
+		return err
+	}
+	deprecations, err := loadDeprecations()
+	if err != nil {
+		return err
+	}
+	violations := findFormatVersionViolations(records, deprecations, min, max)
+
+	rows := make([][]string, 0, len(violations))
+	for _, violation := range violations {
+		rows = append(rows, []string{violation.Path, violation.FormatVersion, violation.Reason})
+	}
+	writeReport(writer, violations, []string{"Path", "Format Version", "Reason"}, rows)
+	return nil
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+	"github.com/projectdiscovery/templates-stats/pkg/templatestats"
+)
+
+// buildAuthorIndex maps each author to the sorted ids of their templates
+func buildAuthorIndex(records []templateRecord) map[string][]string {
+	index := make(map[string][]string)
+	for _, record := range records {
+		for _, author := range templateAuthors(record) {
+			index[author] = append(index[author], record.ID)
+		}
+	}
+	return truncateIndex(index, 0)
+}
+
+// buildTagIndex maps each tag to the sorted ids of templates carrying it.
+// When n is non-zero only the n most used tags are kept.
+func buildTagIndex(records []templateRecord, n int) map[string][]string {
+	index := make(map[string][]string)
+	for _, record := range records {
+		for _, tag := range templatestats.TemplateTags(record) {
+			index[tag] = append(index[tag], record.ID)
+		}
+	}
+	return truncateIndex(index, n)
+}
+
+// buildSeverityIndex maps each severity to the sorted ids of its templates
+func buildSeverityIndex(records []templateRecord) map[string][]string {
+	index := make(map[string][]string)
+	for _, record := range records {
+		severity, ok := record.Info["severity"]
+		if !ok {
+			continue
+		}
+		severityStr := strings.ToLower(types.ToString(severity))
+		index[severityStr] = append(index[severityStr], record.ID)
+	}
+	return truncateIndex(index, 0)
+}
+
+// truncateIndex sorts the ids of an index and keeps the n largest entries
+func truncateIndex(index map[string][]string, n int) map[string][]string {
+	counts := make(map[string]int, len(index))
+	for key, ids := range index {
+		sort.Strings(ids)
+		counts[key] = len(ids)
+	}
+	if n == 0 {
+		return index
+	}
+	truncated := make(map[string][]string, n)
+	for _, pair := range newPairListFromMap(counts, n) {
+		truncated[pair.Key] = index[pair.Key]
+	}
+	return truncated
+}
+
+// writeCoverageMap writes a coverage index as json to the file at path
+func writeCoverageMap(path string, index map[string][]string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "could not create index file")
+	}
+	defer f.Close()
+
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(index); err != nil {
+		return errors.Wrap(err, "could not encode index")
+	}
+	return nil
+}
+
+// writeTemplatePathReport writes the absolute path of every processed template to path
+func writeTemplatePathReport(path string, records []templateRecord) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "could not create path report file")
+	}
+	defer f.Close()
+
+	for _, record := range records {
+		templatePath, err := filepath.Abs(record.Path)
+		if err != nil {
+			templatePath = record.Path
+		}
+		if _, err := f.WriteString(normalizePath(templatePath) + "\n"); err != nil {
+			return errors.Wrap(err, "could not write path report")
+		}
+	}
+	return nil
+}
+
+// writeRawStats writes the complete untruncated counts of a category to path
+func writeRawStats(path string, counts map[string]int) error {
+	data, err := json.Marshal(counts)
+	if err != nil {
+		return errors.Wrap(err, "could not encode raw stats")
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return errors.Wrap(err, "could not write raw stats file")
+	}
+	return nil
+}
+
+// heatmapTags is the number of most used tags included in the coverage heatmap
+const heatmapTags = 20
+
+// heatmapSeverities are the severity columns of the coverage heatmap
+var heatmapSeverities = []string{"critical", "high", "medium", "low", "info", "unknown"}
+
+// buildCoverageHeatmap counts the templates of every severity for the most
+// used tags, the tags are returned in order of usage.
+func buildCoverageHeatmap(records []templateRecord, tagMap map[string]int, n int) ([]string, map[string]map[string]int) {
+	counts := normalizedTagCounts(tagMap)
+	pairs := make(PairList, 0, len(counts))
+	for tag, value := range counts {
+		pairs = append(pairs, Pair{Key: tag, Value: value})
+	}
+	sort.Sort(templatestats.StablePairList(pairs))
+	if len(pairs) > n {
+		pairs = pairs[:n]
+	}
+
+	tags := make([]string, 0, len(pairs))
+	heatmap := make(map[string]map[string]int, len(pairs))
+	for _, pair := range pairs {
+		tags = append(tags, pair.Key)
+		heatmap[pair.Key] = make(map[string]int)
+	}
+	for _, record := range records {
+		severity := strings.ToLower(types.ToString(record.Info["severity"]))
+		if severity == "" {
+			severity = "unknown"
+		}
+		seen := make(map[string]struct{})
+		for _, tag := range templatestats.TemplateTags(record) {
+			tag = strings.ToLower(strings.TrimSpace(tag))
+			row, ok := heatmap[tag]
+			if !ok {
+				continue
+			}
+			if _, ok := seen[tag]; ok {
+				continue
+			}
+			seen[tag] = struct{}{}
+			row[severity]++
+		}
+	}
+	return tags, heatmap
+}
+
+// writeCoverageHeatmapCSV writes the tag x severity counts of the most used
+// tags as a csv to path.
+func writeCoverageHeatmapCSV(path string, records []templateRecord, tagMap map[string]int) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "could not create coverage heatmap csv")
+	}
+	defer file.Close()
+
+	tags, heatmap := buildCoverageHeatmap(records, tagMap, heatmapTags)
+	writer := csv.NewWriter(file)
+	if err := writer.Write(append([]string{"tag"}, heatmapSeverities...)); err != nil {
+		return errors.Wrap(err, "could not write coverage heatmap csv")
+	}
+	for _, tag := range tags {
+		row := []string{tag}
+		for _, severity := range heatmapSeverities {
+			row = append(row, strconv.Itoa(heatmap[tag][severity]))
+		}
+		if err := writer.Write(row); err != nil {
+			return errors.Wrap(err, "could not write coverage heatmap csv")
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return errors.Wrap(err, "could not write coverage heatmap csv")
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+	"github.com/projectdiscovery/templates-stats/pkg/templatestats"
+)
+
+type SeverityMismatch struct {
+	Path          string  `json:"path"`
+	CvssScore     float64 `json:"cvss_score"`
+	CvssGrade     string  `json:"cvss_grade"`
+	SeverityLabel string  `json:"severity_label"`
+}
+
+// findSeverityMismatches returns the templates whose CVSS grade contradicts
+// their info.severity label.
+func findSeverityMismatches(records []templateRecord) []SeverityMismatch {
+	var mismatches []SeverityMismatch
+	for _, record := range records {
+		score, ok := templatestats.TemplateCVSSScore(record)
+		if !ok {
+			continue
+		}
+		severity := strings.ToLower(types.ToString(record.Info["severity"]))
+		if templatestats.CVSSGradeAgrees(score, severity) {
+			continue
+		}
+		mismatches = append(mismatches, SeverityMismatch{Path: record.Path, CvssScore: score, CvssGrade: templatestats.CVSSGrade(score), SeverityLabel: severity})
+	}
+	sort.Slice(mismatches, func(i, j int) bool { return mismatches[i].Path < mismatches[j].Path })
+	return mismatches
+}
+
+func printSeverityMismatches(records []templateRecord, writer io.Writer) {
+	mismatches := findSeverityMismatches(records)
+
+	rows := make([][]string, 0, len(mismatches))
+	for _, mismatch := range mismatches {
+		rows = append(rows, []string{mismatch.Path, strconv.FormatFloat(mismatch.CvssScore, 'f', 1, 64), mismatch.CvssGrade, mismatch.SeverityLabel})
+	}
+	writeReport(writer, mismatches, []string{"Path", "CVSS Score", "CVSS Grade", "Severity"}, rows)
+}
+
+type AlignmentViolation struct {
+	Path             string  `json:"path"`
+	CvssScore        float64 `json:"cvss_score"`
+	CvssGrade        string  `json:"cvss_grade"`
+	DeclaredSeverity string  `json:"declared_severity"`
+}
+
+// findAlignmentViolations returns the templates whose info.severity differs
+// from the severity rating of their CVSS score.
+func findAlignmentViolations(records []templateRecord) []AlignmentViolation {
+	var violations []AlignmentViolation
+	for _, record := range records {
+		score, ok := templatestats.TemplateCVSSScore(record)
+		if !ok {
+			continue
+		}
+		declared := strings.ToLower(types.ToString(record.Info["severity"]))
+		if !templatestats.CVSSAgrees(score, declared) {
+			violations = append(violations, AlignmentViolation{Path: record.Path, CvssScore: score, CvssGrade: templatestats.CVSSSeverity(score), DeclaredSeverity: declared})
+		}
+	}
+	sort.Slice(violations, func(i, j int) bool { return violations[i].Path < violations[j].Path })
+	return violations
+}
+
+func printAlignmentViolations(records []templateRecord, writer io.Writer) {
+	violations := findAlignmentViolations(records)
+
+	rows := make([][]string, 0, len(violations))
+	for _, violation := range violations {
+		rows = append(rows, []string{violation.Path, strconv.FormatFloat(violation.CvssScore, 'f', 1, 64), violation.CvssGrade, violation.DeclaredSeverity})
+	}
+	writeReport(writer, violations, []string{"Path", "CVSS Score", "CVSS Severity", "Declared Severity"}, rows)
+}
+
+type SeverityExplanation struct {
+	ID           string   `json:"id"`
+	Path         string   `json:"path"`
+	RawSeverity  string   `json:"raw_severity"`
+	Severity     string   `json:"severity"`
+	Canonical    bool     `json:"canonical"`
+	CvssScore    *float64 `json:"cvss_score,omitempty"`
+	CvssGrade    string   `json:"cvss_grade,omitempty"`
+	CvssSeverity string   `json:"cvss_severity,omitempty"`
+	// GradeAgrees and RatingAgrees are only set for templates with a CVSS score
+	GradeAgrees  *bool `json:"grade_agrees,omitempty"`
+	RatingAgrees *bool `json:"rating_agrees,omitempty"`
+}
+
+// explainSeverity walks through the severity checks of a single template
+func explainSeverity(record templateRecord) *SeverityExplanation {
+	raw := types.ToString(record.Info["severity"])
+	explanation := &SeverityExplanation{
+		ID:          record.ID,
+		Path:        record.Path,
+		RawSeverity: raw,
+		Severity:    strings.ToLower(strings.TrimSpace(raw)),
+	}
+	explanation.Canonical = templatestats.IsValidSeverity(explanation.Severity)
+	if score, ok := templatestats.TemplateCVSSScore(record); ok {
+		explanation.CvssScore = &score
+		explanation.CvssGrade = templatestats.CVSSGrade(score)
+		explanation.CvssSeverity = templatestats.CVSSSeverity(score)
+		gradeAgrees := templatestats.CVSSGradeAgrees(score, explanation.Severity)
+		ratingAgrees := templatestats.CVSSAgrees(score, explanation.Severity)
+		explanation.GradeAgrees, explanation.RatingAgrees = &gradeAgrees, &ratingAgrees
+	}
+	return explanation
+}
+
+// yesNo formats a boolean check result
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
+
+func printSeverityExplanation(directory, id string, writer io.Writer) error {
+	record, err := findTemplateByID(directory, id)
+	if err != nil {
+		return err
+	}
+	explanation := explainSeverity(*record)
+
+	rows := [][]string{
+		{"Template", explanation.ID + " (" + explanation.Path + ")"},
+		{"Parsed severity", strconv.Quote(explanation.RawSeverity)},
+		{"Normalized severity", explanation.Severity},
+		{"Canonical value", yesNo(explanation.Canonical)},
+	}
+	if explanation.CvssScore == nil {
+		rows = append(rows, []string{"CVSS score", "not present"})
+	} else {
+		rows = append(rows,
+			[]string{"CVSS score", strconv.FormatFloat(*explanation.CvssScore, 'f', 1, 64)},
+			[]string{"CVSS grade", explanation.CvssGrade + " (" + strings.Join(templatestats.CVSSGradeSeverities[explanation.CvssGrade], ", ") + ")"},
+			[]string{"Grade agrees with label", yesNo(*explanation.GradeAgrees)},
+			[]string{"CVSS v3 rating", explanation.CvssSeverity},
+			[]string{"Rating agrees with label", yesNo(*explanation.RatingAgrees)},
+		)
+	}
+	writeReport(writer, explanation, []string{"Step", "Result"}, rows)
+	return nil
+}
@@ -0,0 +1,20 @@
+package main
+
+import "testing"
+
+func TestSeverityMismatchesUseGrades(t *testing.T) {
+	records := []templateRecord{{
+		Path: "cves/info.yaml",
+		Info: map[interface{}]interface{}{
+			"severity":       "info",
+			"classification": map[interface{}]interface{}{"cvss-score": 2.0},
+		},
+	}}
+
+	if mismatches := findSeverityMismatches(records); len(mismatches) != 0 {
+		t.Fatalf("grade A covers info, got mismatches %v", mismatches)
+	}
+	if violations := findAlignmentViolations(records); len(violations) != 1 || violations[0].CvssGrade != "low" {
+		t.Fatalf("a 2.0 score is rated low, got violations %v", violations)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+)
+
+// topLevelDirectory returns the first directory of a template relative to directory
+func topLevelDirectory(directory, path string) string {
+	relative := templateRelativePath(directory, path)
+	if index := strings.Index(relative, "/"); index != -1 {
+		return relative[:index]
+	}
+	return relative
+}
+
+// buildDirectorySeverityMap counts the templates of each severity per top level directory
+func buildDirectorySeverityMap(records []templateRecord, directory string) map[string]map[string]int {
+	dirSeverityMap := make(map[string]map[string]int)
+	for _, record := range records {
+		severity, ok := record.Info["severity"]
+		if !ok {
+			continue
+		}
+		dir := topLevelDirectory(directory, record.Path)
+		if _, ok := dirSeverityMap[dir]; !ok {
+			dirSeverityMap[dir] = make(map[string]int)
+		}
+		dirSeverityMap[dir][strings.ToLower(types.ToString(severity))]++
+	}
+	return dirSeverityMap
+}
+
+// renderDOTSeverityDirectory writes a graphviz bipartite graph connecting
+// the directories of output to their severities weighted by template count.
+func renderDOTSeverityDirectory(output *Output, dirSeverityMap map[string]map[string]int, writer io.Writer) {
+	directories := make([]string, 0, len(output.Directory))
+	for _, pair := range output.Directory {
+		if _, ok := dirSeverityMap[pair.Key]; ok {
+			directories = append(directories, pair.Key)
+		}
+	}
+
+	severities := make(map[string]struct{})
+	for _, dir := range directories {
+		for severity := range dirSeverityMap[dir] {
+			severities[severity] = struct{}{}
+		}
+	}
+	severityNodes := make([]string, 0, len(severities))
+	for severity := range severities {
+		severityNodes = append(severityNodes, severity)
+	}
+	sort.Slice(severityNodes, func(i, j int) bool { return severityRank(severityNodes[i]) > severityRank(severityNodes[j]) })
+
+	fmt.Fprintln(writer, "digraph templates {")
+	fmt.Fprintln(writer, "  rankdir=LR;")
+	for _, dir := range directories {
+		fmt.Fprintf(writer, "  %q [shape=box, label=%q];\n", "dir:"+dir, dir)
+	}
+	for _, severity := range severityNodes {
+		fmt.Fprintf(writer, "  %q [shape=ellipse, label=%q];\n", "severity:"+severity, severity)
+	}
+	for _, dir := range directories {
+		for _, severity := range severityNodes {
+			if weight, ok := dirSeverityMap[dir][severity]; ok {
+				fmt.Fprintf(writer, "  %q -> %q [weight=%d, label=\"%d\"];\n", "dir:"+dir, "severity:"+severity, weight, weight)
+			}
+		}
+	}
+	fmt.Fprintln(writer, "}")
+}
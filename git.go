@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bufio"
+	"os/exec"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// runGit runs a git command in directory and returns its output
+func runGit(directory string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", directory}, args...)...)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", errors.Wrapf(err, "could not run git %s", strings.Join(args, " "))
+	}
+	return string(output), nil
+}
+
+type RenamedTemplate struct {
+	OldPath string `json:"old_path"`
+	NewPath string `json:"new_path"`
+}
+
+// parseRenameLine parses a git summary line such as
+// "rename cves/{old.yaml => new.yaml} (100%)" into its old and new paths.
+func parseRenameLine(line string) (string, string, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "rename ") {
+		return "", "", false
+	}
+	line = strings.TrimPrefix(line, "rename ")
+	if index := strings.LastIndex(line, " ("); index != -1 {
+		line = line[:index]
+	}
+
+	start, end := strings.Index(line, "{"), strings.Index(line, "}")
+	if start == -1 || end < start {
+		parts := strings.SplitN(line, " => ", 2)
+		if len(parts) != 2 {
+			return "", "", false
+		}
+		return parts[0], parts[1], true
+	}
+	parts := strings.SplitN(line[start+1:end], " => ", 2)
+	if len(parts) != 2 {
+		return "", "", false
+	}
+	prefix, suffix := line[:start], line[end+1:]
+	return path.Clean(prefix + parts[0] + suffix), path.Clean(prefix + parts[1] + suffix), true
+}
+
+// gitRenames returns the renames found in the git history of directory
+// mapped from the old path to the latest path of the template.
+func gitRenames(directory string) (map[string]string, error) {
+	output, err := runGit(directory, "log", "--diff-filter=R", "--summary", "--format=", "-M")
+	if err != nil {
+		return nil, err
+	}
+	edges := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		oldPath, newPath, ok := parseRenameLine(scanner.Text())
+		if !ok {
+			continue
+		}
+		// the log is newest first, a path renamed several times keeps its latest rename
+		if _, ok := edges[oldPath]; !ok {
+			edges[oldPath] = newPath
+		}
+	}
+	return resolveRenames(edges), nil
+}
+
+// resolveRenames follows every rename chain such as a => b => c to its last
+// path. A chain renaming back to one of its paths stops before repeating it.
+func resolveRenames(edges map[string]string) map[string]string {
+	renames := make(map[string]string, len(edges))
+	for oldPath, newPath := range edges {
+		seen := map[string]struct{}{oldPath: {}}
+		for {
+			next, ok := edges[newPath]
+			if !ok {
+				break
+			}
+			if _, ok := seen[next]; ok {
+				break
+			}
+			seen[newPath] = struct{}{}
+			newPath = next
+		}
+		renames[oldPath] = newPath
+	}
+	return renames
+}
+
+// renamedTargets returns the set of paths which were created by a rename
+// mapped to the old path. Paths renamed from several old paths map to the
+// first one in order so the result does not depend on map iteration.
+func renamedTargets(renames map[string]string) map[string]string {
+	oldPaths := make([]string, 0, len(renames))
+	for oldPath := range renames {
+		oldPaths = append(oldPaths, oldPath)
+	}
+	sort.Strings(oldPaths)
+
+	targets := make(map[string]string, len(renames))
+	for _, oldPath := range oldPaths {
+		if _, ok := targets[renames[oldPath]]; !ok {
+			targets[renames[oldPath]] = oldPath
+		}
+	}
+	return targets
+}
+
+func findRenamedTemplates(directory string) ([]RenamedTemplate, error) {
+	renames, err := gitRenames(directory)
+	if err != nil {
+		return nil, err
+	}
+	renamed := make([]RenamedTemplate, 0, len(renames))
+	for oldPath, newPath := range renames {
+		if !strings.HasSuffix(newPath, ".yaml") {
+			continue
+		}
+		renamed = append(renamed, RenamedTemplate{OldPath: oldPath, NewPath: newPath})
+	}
+	sort.Slice(renamed, func(i, j int) bool { return renamed[i].NewPath < renamed[j].NewPath })
+	return renamed, nil
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// grafanaTopTags is the number of tags shown in the bar gauge panel
+const grafanaTopTags = 20
+
+// grafanaTopAuthors is the number of authors shown in the table panel
+// unless -top asks for another number.
+const grafanaTopAuthors = 20
+
+type grafanaDashboard struct {
+	Title         string         `json:"title"`
+	Tags          []string       `json:"tags"`
+	Timezone      string         `json:"timezone"`
+	SchemaVersion int            `json:"schemaVersion"`
+	Time          grafanaTime    `json:"time"`
+	Panels        []grafanaPanel `json:"panels"`
+}
+
+type grafanaTime struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type grafanaPanel struct {
+	ID              int                     `json:"id"`
+	Type            string                  `json:"type"`
+	Title           string                  `json:"title"`
+	GridPos         grafanaGridPos          `json:"gridPos"`
+	Datasource      grafanaDatasource       `json:"datasource"`
+	Targets         []grafanaTarget         `json:"targets"`
+	Options         map[string]interface{}  `json:"options,omitempty"`
+	Transformations []grafanaTransformation `json:"transformations,omitempty"`
+}
+
+type grafanaGridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type grafanaDatasource struct {
+	Type string `json:"type"`
+}
+
+type grafanaTarget struct {
+	RefID      string `json:"refId"`
+	Target     string `json:"target"`
+	TextEditor bool   `json:"textEditor"`
+}
+
+type grafanaTransformation struct {
+	ID      string                 `json:"id"`
+	Options map[string]interface{} `json:"options"`
+}
+
+// renderGrafana writes a grafana dashboard with the stats embedded as
+// static graphite constantLine series so it can be imported as-is.
+func renderGrafana(output *Output, total int, writer io.Writer) error {
+	reduceOptions := map[string]interface{}{
+		"calcs":  []string{"lastNotNull"},
+		"values": false,
+	}
+	dashboard := &grafanaDashboard{
+		Title:         "Nuclei Templates Stats",
+		Tags:          []string{"nuclei", "templates-stats"},
+		Timezone:      "browser",
+		SchemaVersion: 36,
+		Time:          grafanaTime{From: "now-6h", To: "now"},
+		Panels: []grafanaPanel{
+			{
+				ID:         1,
+				Type:       "stat",
+				Title:      "Total Templates",
+				GridPos:    grafanaGridPos{H: 8, W: 6, X: 0, Y: 0},
+				Datasource: grafanaDatasource{Type: "graphite"},
+				Targets:    grafanaTargets(PairList{{Key: "templates", Value: total}}),
+				Options:    map[string]interface{}{"reduceOptions": reduceOptions},
+			},
+			{
+				ID:         2,
+				Type:       "piechart",
+				Title:      "Severity Distribution",
+				GridPos:    grafanaGridPos{H: 8, W: 9, X: 6, Y: 0},
+				Datasource: grafanaDatasource{Type: "graphite"},
+				Targets:    grafanaTargets(output.Severity),
+				Options:    map[string]interface{}{"reduceOptions": reduceOptions, "pieType": "pie"},
+			},
+			{
+				ID:         3,
+				Type:       "bargauge",
+				Title:      fmt.Sprintf("Top %d Tags", grafanaTopTags),
+				GridPos:    grafanaGridPos{H: 8, W: 9, X: 15, Y: 0},
+				Datasource: grafanaDatasource{Type: "graphite"},
+				Targets:    grafanaTargets(output.Tags),
+				Options:    map[string]interface{}{"reduceOptions": reduceOptions, "orientation": "horizontal"},
+			},
+			{
+				ID:         4,
+				Type:       "table",
+				Title:      "Top Authors",
+				GridPos:    grafanaGridPos{H: 12, W: 24, X: 0, Y: 8},
+				Datasource: grafanaDatasource{Type: "graphite"},
+				Targets:    grafanaTargets(output.Authors),
+				Transformations: []grafanaTransformation{
+					{ID: "reduce", Options: map[string]interface{}{"reducers": []string{"lastNotNull"}}},
+				},
+			},
+		},
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(dashboard)
+}
+
+// grafanaTargets converts a pair list into graphite text-mode targets
+func grafanaTargets(pairs PairList) []grafanaTarget {
+	targets := make([]grafanaTarget, 0, len(pairs))
+	for i, pair := range pairs {
+		name := strings.ReplaceAll(pair.Key, "'", "")
+		targets = append(targets, grafanaTarget{
+			RefID:      grafanaRefID(i),
+			Target:     fmt.Sprintf("alias(constantLine(%d), '%s')", pair.Value, name),
+			TextEditor: true,
+		})
+	}
+	return targets
+}
+
+// grafanaRefID returns a spreadsheet style reference id (A..Z, AA..)
+func grafanaRefID(i int) string {
+	id := ""
+	for i >= 0 {
+		id = string(rune('A'+i%26)) + id
+		i = i/26 - 1
+	}
+	return id
+}
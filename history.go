@@ -0,0 +1,335 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/templates-stats/pkg/templatestats"
+)
+
+// commitHistoryTop is the number of authors shown without -author-filter
+const commitHistoryTop = 10
+
+// sparklineLevels are the ascii characters of the commit history series from
+// no commits to the busiest month.
+const sparklineLevels = " .:-=+*#"
+
+// authorMonthlyCommits returns the number of commits of author per YYYY-MM month
+func authorMonthlyCommits(directory, author string) (map[string]int, error) {
+	output, err := runGit(directory, "log", "--regexp-ignore-case", "--author="+regexp.QuoteMeta(author), "--format=%ad", "--date=format:%Y-%m")
+	if err != nil {
+		return nil, err
+	}
+	months := make(map[string]int)
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		if month := strings.TrimSpace(scanner.Text()); month != "" {
+			months[month]++
+		}
+	}
+	return months, nil
+}
+
+// computeAuthorCommitHistory returns the monthly commit counts of every author
+func computeAuthorCommitHistory(directory string, authors []string) (map[string]map[string]int, error) {
+	history := make(map[string]map[string]int, len(authors))
+	for _, author := range authors {
+		months, err := authorMonthlyCommits(directory, author)
+		if err != nil {
+			return nil, err
+		}
+		history[author] = months
+	}
+	return history, nil
+}
+
+// monthRange returns every YYYY-MM month between the first and last month of history
+func monthRange(history map[string]map[string]int) []string {
+	var first, last string
+	for _, months := range history {
+		for month := range months {
+			if first == "" || month < first {
+				first = month
+			}
+			if month > last {
+				last = month
+			}
+		}
+	}
+	start, err := time.Parse("2006-01", first)
+	if err != nil {
+		return nil
+	}
+	end, err := time.Parse("2006-01", last)
+	if err != nil {
+		return nil
+	}
+	var months []string
+	for month := start; !month.After(end); month = month.AddDate(0, 1, 0) {
+		months = append(months, month.Format("2006-01"))
+	}
+	return months
+}
+
+// sparkline renders the counts of months as a single line of ascii levels
+func sparkline(counts map[string]int, months []string) string {
+	max := 0
+	for _, month := range months {
+		if counts[month] > max {
+			max = counts[month]
+		}
+	}
+	var builder strings.Builder
+	for _, month := range months {
+		level := 0
+		if max > 0 && counts[month] > 0 {
+			level = 1 + (counts[month]*(len(sparklineLevels)-2))/max
+		}
+		builder.WriteByte(sparklineLevels[level])
+	}
+	return builder.String()
+}
+
+func printAuthorCommitHistory(directory string, authorMap map[string]int, filter string, writer io.Writer) error {
+	var authors []string
+	if filter != "" {
+		authors = templatestats.ExplodeCommaSeparatedField(filter)
+	} else {
+		for _, pair := range newPairListFromMap(authorMap, commitHistoryTop) {
+			authors = append(authors, pair.Key)
+		}
+	}
+	history, err := computeAuthorCommitHistory(directory, authors)
+	if err != nil {
+		return err
+	}
+	if *jsonOutput {
+		if err := json.NewEncoder(writer).Encode(history); err != nil {
+			log.Fatalf("Could not encode json: %s\n", err)
+		}
+		return nil
+	}
+
+	months := monthRange(history)
+	sort.Strings(authors)
+	rows := make([][]string, 0, len(authors))
+	for _, author := range authors {
+		total := 0
+		for _, value := range history[author] {
+			total += value
+		}
+		rows = append(rows, []string{author, strconv.Itoa(total), sparkline(history[author], months)})
+	}
+	header := []string{"Author", "Commits", "History"}
+	if len(months) > 0 {
+		header[2] = "History " + months[0] + " - " + months[len(months)-1]
+	}
+	renderTable(writer, header, rows)
+	return nil
+}
+
+type AuthorJoinDate struct {
+	Author                string    `json:"author"`
+	FirstContributionDate time.Time `json:"first_contribution_date"`
+	DaysSinceFirst        int       `json:"days_since_first"`
+	TotalTemplates        int       `json:"total_templates"`
+	TemplatesPerMonth     float64   `json:"templates_per_month"`
+}
+
+// templateCommit is a commit of the git history changing templates
+type templateCommit struct {
+	// Author is the lower case "name <email>" matched by git log --author
+	Author string
+	Date   time.Time
+	// Added is the number of template lines added by the commit
+	Added int
+}
+
+// matchesAuthor reports whether the commit matches author the same way as
+// git log --regexp-ignore-case --author with a quoted pattern.
+func (c templateCommit) matchesAuthor(author string) bool {
+	return strings.Contains(c.Author, strings.ToLower(author))
+}
+
+// templateCommits returns the commits changing templates newest first in a
+// single git log pass, args are additional git log arguments.
+func templateCommits(directory string, args ...string) ([]templateCommit, error) {
+	gitArgs := append([]string{"log", "--numstat", "--format=format:%x00%an <%ae>%x00%aI"}, args...)
+	output, err := runGit(directory, append(gitArgs, "--", "*.yaml")...)
+	if err != nil {
+		return nil, err
+	}
+	var commits []templateCommit
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "\x00") {
+			parts := strings.SplitN(line[1:], "\x00", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			date, err := time.Parse(time.RFC3339, parts[1])
+			if err != nil {
+				return nil, err
+			}
+			commits = append(commits, templateCommit{Author: strings.ToLower(parts[0]), Date: date})
+			continue
+		}
+		// numstat lines are "<added>\t<deleted>\t<path>", binary files use "-"
+		fields := strings.Fields(line)
+		if len(fields) < 3 || len(commits) == 0 {
+			continue
+		}
+		if value, err := strconv.Atoi(fields[0]); err == nil {
+			commits[len(commits)-1].Added += value
+		}
+	}
+	return commits, nil
+}
+
+// computeAuthorJoinDates returns the first contribution of every author of
+// authorMap along with their template rate since then, the oldest first.
+// Templates without an author are left out as they match every commit.
+func computeAuthorJoinDates(directory string, authorMap map[string]int, now time.Time) ([]AuthorJoinDate, error) {
+	commits, err := templateCommits(directory, "--diff-filter=A")
+	if err != nil {
+		return nil, err
+	}
+	joinDates := make([]AuthorJoinDate, 0, len(authorMap))
+	for author, total := range authorMap {
+		if author == "" {
+			continue
+		}
+		var first time.Time
+		// the log is newest first so the last match is the first contribution
+		for _, commit := range commits {
+			if commit.matchesAuthor(author) {
+				first = commit.Date
+			}
+		}
+		if first.IsZero() {
+			continue
+		}
+		days := int(now.Sub(first).Hours() / 24)
+		months := float64(days) / 30
+		if months < 1 {
+			months = 1
+		}
+		joinDates = append(joinDates, AuthorJoinDate{
+			Author:                author,
+			FirstContributionDate: first,
+			DaysSinceFirst:        days,
+			TotalTemplates:        total,
+			TemplatesPerMonth:     float64(total) / months,
+		})
+	}
+	sort.Slice(joinDates, func(i, j int) bool {
+		if !joinDates[i].FirstContributionDate.Equal(joinDates[j].FirstContributionDate) {
+			return joinDates[i].FirstContributionDate.Before(joinDates[j].FirstContributionDate)
+		}
+		return joinDates[i].Author < joinDates[j].Author
+	})
+	return joinDates, nil
+}
+
+func printAuthorJoinDates(directory string, authorMap map[string]int, filter string, writer io.Writer) error {
+	if filter != "" {
+		filtered := make(map[string]int)
+		for _, author := range templatestats.ExplodeCommaSeparatedField(filter) {
+			filtered[author] = authorMap[author]
+		}
+		authorMap = filtered
+	}
+	joinDates, err := computeAuthorJoinDates(directory, authorMap, time.Now())
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(joinDates))
+	for _, item := range joinDates {
+		rows = append(rows, []string{
+			item.Author,
+			item.FirstContributionDate.Format("2006-01-02"),
+			strconv.Itoa(item.DaysSinceFirst),
+			strconv.Itoa(item.TotalTemplates),
+			strconv.FormatFloat(item.TemplatesPerMonth, 'f', 2, 64),
+		})
+	}
+	writeReport(writer, joinDates, []string{"Author", "First Contribution", "Days Since", "Templates", "Templates/Month"}, rows)
+	return nil
+}
+
+type AuthorDiffSize struct {
+	Author              string  `json:"author"`
+	Commits             int     `json:"commits"`
+	AvgLinesPerTemplate float64 `json:"avg_lines_per_template"`
+	TotalLinesAdded     int     `json:"total_lines_added"`
+}
+
+// computeAuthorDiffSizes returns the average template lines added per commit
+// of every author, the largest average first. Templates without an author
+// are left out as they match every commit.
+func computeAuthorDiffSizes(directory string, authors []string) ([]AuthorDiffSize, error) {
+	commits, err := templateCommits(directory)
+	if err != nil {
+		return nil, err
+	}
+	sizes := make([]AuthorDiffSize, 0, len(authors))
+	for _, author := range authors {
+		if author == "" {
+			continue
+		}
+		var count, added int
+		for _, commit := range commits {
+			if commit.matchesAuthor(author) {
+				count++
+				added += commit.Added
+			}
+		}
+		if count == 0 {
+			continue
+		}
+		sizes = append(sizes, AuthorDiffSize{
+			Author:              author,
+			Commits:             count,
+			AvgLinesPerTemplate: float64(added) / float64(count),
+			TotalLinesAdded:     added,
+		})
+	}
+	sort.Slice(sizes, func(i, j int) bool {
+		if sizes[i].AvgLinesPerTemplate != sizes[j].AvgLinesPerTemplate {
+			return sizes[i].AvgLinesPerTemplate > sizes[j].AvgLinesPerTemplate
+		}
+		return sizes[i].Author < sizes[j].Author
+	})
+	return sizes, nil
+}
+
+func printAuthorDiffSizes(directory string, authorMap map[string]int, filter string, writer io.Writer) error {
+	var authors []string
+	if filter != "" {
+		authors = templatestats.ExplodeCommaSeparatedField(filter)
+	} else {
+		for author := range authorMap {
+			authors = append(authors, author)
+		}
+	}
+	sizes, err := computeAuthorDiffSizes(directory, authors)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(sizes))
+	for _, size := range sizes {
+		rows = append(rows, []string{size.Author, strconv.Itoa(size.Commits), strconv.FormatFloat(size.AvgLinesPerTemplate, 'f', 1, 64), strconv.Itoa(size.TotalLinesAdded)})
+	}
+	writeReport(writer, sizes, []string{"Author", "Commits", "Avg Lines", "Total Lines Added"}, rows)
+	return nil
+}
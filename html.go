@@ -0,0 +1,136 @@
+package main
+
+import (
+	"html/template"
+	"io"
+
+	"github.com/projectdiscovery/templates-stats/pkg/templatestats"
+)
+
+var leaderboardTemplate = template.Must(template.New("leaderboard").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Nuclei Templates Author Leaderboard</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292f; }
+h1 { font-size: 1.6em; }
+input { padding: 6px 10px; margin-bottom: 1em; width: 300px; border: 1px solid #d0d7de; border-radius: 6px; }
+table { border-collapse: collapse; width: 100%; }
+th, td { padding: 6px 12px; border-bottom: 1px solid #d0d7de; text-align: left; }
+th { cursor: pointer; background: #f6f8fa; user-select: none; }
+th:hover { background: #eaeef2; }
+td.number { text-align: right; }
+</style>
+</head>
+<body>
+<h1>Nuclei Templates Author Leaderboard</h1>
+<input id="search" type="search" placeholder="Filter authors..." onkeyup="filterRows()">
+<table id="leaderboard">
+<thead>
+<tr>
+<th data-type="number">Rank</th>
+<th data-type="string">Author</th>
+<th data-type="number">Templates</th>
+<th data-type="number">% of Total</th>
+<th data-type="number">CVE</th>
+<th data-type="number">Non-CVE</th>
+<th data-type="string">Top Tag</th>
+<th data-type="string">Top Severity</th>
+</tr>
+</thead>
+<tbody>
+{{- range .}}
+<tr>
+<td class="number">{{.Rank}}</td>
+<td>{{.Name}}</td>
+<td class="number">{{.Count}}</td>
+<td class="number">{{printf "%.2f" .PctOfTotal}}</td>
+<td class="number">{{.CveCount}}</td>
+<td class="number">{{.NonCveCount}}</td>
+<td>{{.TopTag}}</td>
+<td>{{.TopSeverity}}</td>
+</tr>
+{{- end}}
+</tbody>
+</table>
+<script>
+function filterRows() {
+  var query = document.getElementById("search").value.toLowerCase();
+  document.querySelectorAll("#leaderboard tbody tr").forEach(function (row) {
+    row.style.display = row.cells[1].textContent.toLowerCase().indexOf(query) === -1 ? "none" : "";
+  });
+}
+document.querySelectorAll("#leaderboard th").forEach(function (header, column) {
+  var ascending = false;
+  header.addEventListener("click", function () {
+    ascending = !ascending;
+    var numeric = header.dataset.type === "number";
+    var body = document.querySelector("#leaderboard tbody");
+    Array.from(body.rows).sort(function (a, b) {
+      var first = a.cells[column].textContent, second = b.cells[column].textContent;
+      var result = numeric ? parseFloat(first) - parseFloat(second) : first.localeCompare(second);
+      return ascending ? result : -result;
+    }).forEach(function (row) { body.appendChild(row); });
+  });
+});
+</script>
+</body>
+</html>
+`))
+
+// renderHTMLLeaderboard writes a self-contained sortable author leaderboard page
+func renderHTMLLeaderboard(records []templateRecord, n int, writer io.Writer) error {
+	return leaderboardTemplate.Execute(writer, computeAuthorReports(records, n))
+}
+
+var statsTemplate = template.Must(template.New("stats").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Nuclei Templates Stats</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292f; }
+h1 { font-size: 1.6em; }
+h2 { font-size: 1.2em; margin-top: 1.5em; }
+table { border-collapse: collapse; min-width: 320px; }
+th, td { padding: 6px 12px; border-bottom: 1px solid #d0d7de; text-align: left; }
+th { background: #f6f8fa; }
+td.number { text-align: right; }
+tr.severity-critical { background: #ffd7d5; }
+tr.severity-high { background: #ffe2c4; }
+tr.severity-medium { background: #fff5b1; }
+tr.severity-low { background: #dafbe1; }
+tr.severity-info { background: #ddf4ff; }
+</style>
+</head>
+<body>
+<h1>Nuclei Templates Stats</h1>
+{{- range .}}
+{{- $severity := eq .Category "severity"}}
+<h2>{{.Header}}</h2>
+<table>
+<thead>
+<tr><th>{{.Header}}</th><th>Count</th></tr>
+</thead>
+<tbody>
+{{- range .Pairs}}
+<tr{{if $severity}} class="severity-{{.Key}}"{{end}}><td>{{.Key}}</td><td class="number">{{.Value}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+</body>
+</html>
+`))
+
+// renderHTML writes a self-contained page with one table per populated category
+func renderHTML(output *Output, writer io.Writer) error {
+	var columns []templatestats.Column
+	for _, column := range output.Columns() {
+		if len(column.Pairs) > 0 {
+			columns = append(columns, column)
+		}
+	}
+	return statsTemplate.Execute(writer, columns)
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/catalog/disk"
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+	"github.com/projectdiscovery/templates-stats/pkg/templatestats"
+	stringsutil "github.com/projectdiscovery/utils/strings"
+	"gopkg.in/yaml.v2"
+)
+
+var interactshMarkers = []string{"{{interactsh-url}}", "{{interactsh-payload}}"}
+
+type InteractshTemplate struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Severity string `json:"severity"`
+	Author   string `json:"author"`
+}
+
+// containsAnyString walks a decoded yaml value and reports whether any
+// string inside it contains one of the given substrings.
+func containsAnyString(value interface{}, substrings ...string) bool {
+	switch v := value.(type) {
+	case string:
+		for _, substring := range substrings {
+			if strings.Contains(v, substring) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		for key, item := range v {
+			if containsAnyString(key, substrings...) || containsAnyString(item, substrings...) {
+				return true
+			}
+		}
+	case map[interface{}]interface{}:
+		for key, item := range v {
+			if containsAnyString(key, substrings...) || containsAnyString(item, substrings...) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if containsAnyString(item, substrings...) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func findInteractshTemplates(records []templateRecord) []InteractshTemplate {
+	var templates []InteractshTemplate
+	for _, record := range records {
+		if !containsAnyString(record.Data, interactshMarkers...) {
+			continue
+		}
+		templates = append(templates, InteractshTemplate{
+			ID:       record.ID,
+			Name:     types.ToString(record.Info["name"]),
+			Severity: strings.ToLower(types.ToString(record.Info["severity"])),
+			Author:   types.ToString(record.Info["author"]),
+		})
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].ID < templates[j].ID })
+	return templates
+}
+
+func printInteractshTemplates(records []templateRecord, writer io.Writer) {
+	templates := findInteractshTemplates(records)
+
+	rows := make([][]string, 0, len(templates))
+	for _, template := range templates {
+		rows = append(rows, []string{template.ID, template.Name, template.Severity, template.Author})
+	}
+	writeReport(writer, templates, []string{"ID", "Name", "Severity", "Author"}, rows)
+}
+
+type TemplateInfo struct {
+	ID          string   `json:"id"`
+	Path        string   `json:"path"`
+	Name        string   `json:"name"`
+	Author      string   `json:"author"`
+	Severity    string   `json:"severity"`
+	Tags        string   `json:"tags,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Reference   []string `json:"reference,omitempty"`
+}
+
+// parseTemplateFile decodes the yaml template at path
+func parseTemplateFile(path string) (map[string]interface{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open template")
+	}
+	defer f.Close()
+
+	data := make(map[string]interface{})
+	if err := yaml.NewDecoder(f).Decode(&data); err != nil {
+		return nil, errors.Wrap(err, "could not decode template")
+	}
+	return data, nil
+}
+
+func newTemplateInfo(record templateRecord) TemplateInfo {
+	return TemplateInfo{
+		ID:          record.ID,
+		Path:        record.Path,
+		Name:        types.ToString(record.Info["name"]),
+		Author:      types.ToString(record.Info["author"]),
+		Severity:    strings.ToLower(types.ToString(record.Info["severity"])),
+		Tags:        strings.Join(templatestats.TemplateTags(record), ","),
+		Description: strings.TrimSpace(types.ToString(record.Info["description"])),
+		Reference:   templateReferences(record),
+	}
+}
+
+// findTemplateByID walks the template directory and returns the first template with the id
+func findTemplateByID(directory, id string) (*templateRecord, error) {
+	catalogClient := disk.NewCatalog(directory)
+	includedTemplates, err := catalogClient.GetTemplatePath(directory)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get templates")
+	}
+	for _, template := range includedTemplates {
+		if !stringsutil.EqualFoldAny(filepath.Ext(template), ".yaml") {
+			continue
+		}
+		data, err := parseTemplateFile(template)
+		if err != nil {
+			continue
+		}
+		if types.ToString(data["id"]) != id {
+			continue
+		}
+		infoMap, ok := data["info"].(map[interface{}]interface{})
+		if !ok {
+			return nil, fmt.Errorf("no info found for template %s", template)
+		}
+		return &templateRecord{Path: normalizePath(template), ID: id, Data: data, Info: infoMap}, nil
+	}
+	return nil, fmt.Errorf("no template found with id %s", id)
+}
+
+func printTemplateByID(directory, id string, writer io.Writer) error {
+	record, err := findTemplateByID(directory, id)
+	if err != nil {
+		return err
+	}
+	info := newTemplateInfo(*record)
+
+	rows := [][]string{
+		{"ID", info.ID},
+		{"Path", info.Path},
+		{"Name", info.Name},
+		{"Author", info.Author},
+		{"Severity", info.Severity},
+		{"Tags", info.Tags},
+		{"Description", info.Description},
+		{"Reference", strings.Join(info.Reference, "\n")},
+	}
+	writeReport(writer, info, []string{"Field", "Value"}, rows)
+	return nil
+}
+
+func printRenamedTemplates(directory string, writer io.Writer) error {
+	renamed, err := findRenamedTemplates(directory)
+	if err != nil {
+		return err
+	}
+	rows := make([][]string, 0, len(renamed))
+	for _, template := range renamed {
+		rows = append(rows, []string{template.OldPath, template.NewPath})
+	}
+	writeReport(writer, renamed, []string{"Old Path", "New Path"}, rows)
+	return nil
+}
+
+type MultiTypeTemplate struct {
+	Path  string   `json:"path"`
+	Types []string `json:"types"`
+}
+
+func findMultiTypeTemplates(records []templateRecord) []MultiTypeTemplate {
+	var templates []MultiTypeTemplate
+	for _, record := range records {
+		var requestTypes []string
+		for _, requestType := range templateRequestTypes(record) {
+			if requestType != "workflows" {
+				requestTypes = append(requestTypes, requestType)
+			}
+		}
+		if len(requestTypes) > 1 {
+			templates = append(templates, MultiTypeTemplate{Path: record.Path, Types: requestTypes})
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Path < templates[j].Path })
+	return templates
+}
+
+func printMultiTypeTemplates(records []templateRecord, writer io.Writer) {
+	templates := findMultiTypeTemplates(records)
+
+	rows := make([][]string, 0, len(templates))
+	for _, template := range templates {
+		rows = append(rows, []string{template.Path, strings.Join(template.Types, ", ")})
+	}
+	writeReport(writer, templates, []string{"Path", "Types"}, rows)
+}
+
+// templateRelativePath returns the slash separated path of a template relative to directory
+func templateRelativePath(directory, path string) string {
+	relative, err := filepath.Rel(directory, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(relative)
+}
+
+func findTemplatesInDirectory(records []templateRecord, templateDirectory, directory string) []TemplateInfo {
+	prefix := strings.Trim(filepath.ToSlash(directory), "/") + "/"
+	var templates []TemplateInfo
+	for _, record := range records {
+		if strings.HasPrefix(templateRelativePath(templateDirectory, record.Path), prefix) {
+			templates = append(templates, newTemplateInfo(record))
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].ID < templates[j].ID })
+	return templates
+}
+
+func printTemplatesInDirectory(records []templateRecord, templateDirectory, directory string, writer io.Writer) {
+	templates := findTemplatesInDirectory(records, templateDirectory, directory)
+
+	rows := make([][]string, 0, len(templates))
+	for _, template := range templates {
+		rows = append(rows, []string{template.ID, template.Name, template.Author, template.Severity, template.Tags})
+	}
+	writeReport(writer, templates, []string{"ID", "Name", "Author", "Severity", "Tags"}, rows)
+}
+
+// findTemplatesByProduct returns the templates mentioning product in their
+// name, tags or description, the most severe first.
+func findTemplatesByProduct(records []templateRecord, product string) []TemplateInfo {
+	product = strings.ToLower(strings.TrimSpace(product))
+	var templates []TemplateInfo
+	for _, record := range records {
+		info := newTemplateInfo(record)
+		for _, field := range []string{info.Name, info.Tags, info.Description} {
+			if strings.Contains(strings.ToLower(field), product) {
+				templates = append(templates, info)
+				break
+			}
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		if rankI, rankJ := severityRank(templates[i].Severity), severityRank(templates[j].Severity); rankI != rankJ {
+			return rankI > rankJ
+		}
+		return templates[i].ID < templates[j].ID
+	})
+	return templates
+}
+
+func printTemplatesByProduct(records []templateRecord, product string, writer io.Writer) {
+	templates := findTemplatesByProduct(records, product)
+
+	rows := make([][]string, 0, len(templates))
+	for _, template := range templates {
+		rows = append(rows, []string{template.ID, template.Name, template.Severity, template.Path})
+	}
+	writeReport(writer, templates, []string{"ID", "Name", "Severity", "Path"}, rows)
+}
+
+// writeExcludeList writes the sorted template paths one per line to path, the
+// file can be passed to nuclei -exclude-templates.
+func writeExcludeList(path string, templates []string) error {
+	sorted := make([]string, len(templates))
+	copy(sorted, templates)
+	sort.Strings(sorted)
+
+	var builder strings.Builder
+	for _, template := range sorted {
+		builder.WriteString(template)
+		builder.WriteString("\n")
+	}
+	if err := os.WriteFile(path, []byte(builder.String()), 0644); err != nil {
+		return errors.Wrap(err, "could not write exclude list")
+	}
+	return nil
+}
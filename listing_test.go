@@ -0,0 +1,28 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/projectdiscovery/templates-stats/pkg/templatestats"
+)
+
+func TestFindTemplatesInDirectoryRelativePath(t *testing.T) {
+	previous := *templateDirectory
+	defer func() { *templateDirectory = previous }()
+	*templateDirectory = "pkg/templatestats/testdata/templates"
+
+	cfg := statsConfigFromFlags()
+	stats, err := templatestats.Collect(cfg)
+	if err != nil {
+		t.Fatalf("could not collect stats: %s", err)
+	}
+
+	var ids []string
+	for _, template := range findTemplatesInDirectory(stats.Records, cfg.TemplateDirectory, "http") {
+		ids = append(ids, template.ID)
+	}
+	if expected := []string{"CVE-2023-1234", "git-config"}; !reflect.DeepEqual(ids, expected) {
+		t.Fatalf("unexpected templates %v, want %v", ids, expected)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"encoding/csv"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -9,48 +10,35 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/olekukonko/tablewriter"
 	"github.com/pkg/errors"
-	"github.com/projectdiscovery/nuclei/v2/pkg/catalog/disk"
 	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+	"github.com/projectdiscovery/templates-stats/pkg/templatestats"
 	sliceutil "github.com/projectdiscovery/utils/slice"
 	stringsutil "github.com/projectdiscovery/utils/strings"
 	"gopkg.in/yaml.v2"
 )
 
-type Pair struct {
-	Key   string `json:"name"`
-	Value int    `json:"count"`
-}
-
-type PairList []Pair
-
-func (p PairList) Len() int           { return len(p) }
-func (p PairList) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
-func (p PairList) Less(i, j int) bool { return p[i].Value > p[j].Value }
+type (
+	Pair           = templatestats.Pair
+	PairList       = templatestats.PairList
+	Output         = templatestats.Output
+	StatsConfig    = templatestats.StatsConfig
+	LintIssue      = templatestats.LintIssue
+	DuplicateEntry = templatestats.DuplicateEntry
+	templateRecord = templatestats.Record
+)
 
+// newPairListFromMap returns the n most common entries of data, ties are
+// ordered by key when -strict-json is used.
 func newPairListFromMap(data map[string]int, n int) PairList {
-	pairs := make(PairList, len(data))
-	i := 0
-
-	for k, v := range data {
-		pairs[i] = Pair{k, v}
-		i++
-	}
-	sort.Sort(pairs)
-
-	final := make([]Pair, 0, len(pairs))
-	for i, data := range pairs {
-		if n != 0 && i == n {
-			break
-		}
-		final = append(final, data)
-	}
-	return final
+	return templatestats.NewPairListFromMap(data, n, *strictJSON)
 }
 
 var (
@@ -67,32 +55,152 @@ var (
 	ta                = flag.String("ta", "", "Template Addition file")
 	outputFile        = flag.String("output", "", "File to write template addition author output to")
 	jsonOutput        = flag.Bool("json", false, "Show output in json format")
+	htmlOutput        = flag.Bool("html", false, "Show output as a self-contained html report")
+	jsonLines         = flag.Bool("jsonl", false, "Show output as one json line per pair")
+	csvOutput         = flag.Bool("csv", false, "Show output in csv format")
+	csvDir            = flag.String("csv-dir", "", "Directory to write one csv file per category to")
+	strictJSON        = flag.Bool("strict-json", false, "Show output in deterministic json format with sorted keys and ties")
+	format            = flag.String("format", "", "Output format (grafana,jsonlines,yaml,dot,html-leaderboard,parquet,terminal256), terminal256 is the default on 256 color terminals")
+	authorSinceFirst  = flag.Bool("author-since-first", false, "Show author growth since their first contribution")
+	checkRefsTLS      = flag.Bool("check-external-refs-tls", false, "Verify the tls certificates of the https references")
+	insecureRefs      = flag.Bool("insecure-refs", false, "Skip certificate verification in -check-external-refs-tls and only report connection failures")
+	verifyCveIDs      = flag.Bool("verify-cve-ids", false, "Verify that CVE template ids exist in NVD, ids which could not be checked are reported as unverified")
+	nvdConcurrency    = flag.Int("nvd-concurrency", 2, "Number of concurrent NVD API requests")
+	nvdAPIKey         = flag.String("nvd-api-key", "", "NVD API key for higher rate limits")
+	outputWidth       = flag.Int("output-width", 0, "Maximum width of the rendered table")
+	listInteractsh    = flag.Bool("list-interactsh", false, "List templates using interactsh for out-of-band detection")
+	authorIndex       = flag.String("author-index", "", "File to write author to template ids json index")
+	tagIndex          = flag.String("tag-index", "", "File to write tag to template ids json index")
+	severityIndex     = flag.String("severity-index", "", "File to write severity to template ids json index")
+	validateStructure = flag.Bool("validate-template-structure", false, "Validate that templates have all required fields")
+	cveAuthorsFilter  = flag.Bool("cve-authors", false, "Show Author Data for CVE templates")
+	severityPctChange = flag.Bool("severity-pct-change", false, "Compare severity distribution with a -baseline json output")
+	baseline          = flag.String("baseline", "", "Baseline json output file to compare against")
+	checkMissingInfo  = flag.Bool("check-missing-info-fields", false, "Show templates missing each info field")
+	formatVersion     = flag.String("check-format-version", "", "Show templates with a format-version outside of an inclusive range of dotted versions given as MIN:MAX, e.g. 1.0:2.1")
+	compatCheck       = flag.Bool("compat-check", false, "Show templates using deprecated nuclei keys with the nuclei version deprecating them")
+	extractorTypes    = flag.Bool("extractor-types", false, "Show Extractor Types Data")
+	simulateTop       = flag.Bool("simulate-top", false, "Show the count covered by the top N entries for several N values")
+	authorsHybrid     = flag.Bool("authors-hybrid", false, "Show authors contributing both CVE and non-CVE templates")
+	pathReport        = flag.String("template-path-report", "", "File to write the paths of all processed templates to")
+	qualityFilter     = flag.Bool("classify-template-quality", false, "Show Template Quality Grade Data")
+	authorOverlap     = flag.Bool("author-overlap", false, "Show pairs of authors with the most similar template sets")
+	cveCoverage       = flag.Bool("report-cve-coverage-pct", false, "Compare the CVEs published in NVD during -year against the CVE templates of these CVEs")
+	cveYear           = flag.Int("year", time.Now().Year(), "Year used for CVE coverage")
+	authorRatio       = flag.Bool("author-template-ratio", false, "Show the average number of templates per author")
+	watchNewAuthors   = flag.Bool("watch-authors", false, "Report authors not seen in a previous run")
+	watchAuthorsState = flag.String("watch-authors-state", "", "File storing the known authors for -watch-authors")
+	webhookURL        = flag.String("webhook-url", "", "Webhook URL notified about new authors")
+	descriptionStats  = flag.Bool("description-stats", false, "Show description length statistics")
+	checkAuthorFormat = flag.Bool("check-author-format", false, "Show authors not matching -author-format")
+	authorFormat      = flag.String("author-format", "^[a-zA-Z0-9_-]+$", "Regex authors are validated against")
+	showTemplateID    = flag.String("show-template-by-id", "", "Show the metadata of the template with the id")
+	explainSeverityID = flag.String("explain-severity", "", "Explain the severity rating of the template with the id")
+	severityTrend     = flag.Bool("severity-trend-by-quarter", false, "Show severity distribution per calendar quarter")
+	normalizePaths    = flag.Bool("normalize-paths", false, "Use forward slashes in all reported paths")
+	minReferences     = flag.Int("min-references", 0, "Show templates with fewer references than this")
+	maxReferences     = flag.Int("max-references", 0, "Show templates with more references than this")
+	nucleiConfigGen   = flag.Bool("generate-nuclei-config", false, "Generate a nuclei config scoped to the top tags")
+	configSeverity    = flag.String("config-severity", "critical,high", "Severities included in the generated nuclei config")
+	trackRenames      = flag.Bool("track-renames", false, "Show templates renamed in the git history (excludes renames from -ta)")
+	listMultiType     = flag.Bool("list-multi-type", false, "List templates declaring multiple request types")
+	listByDirectory   = flag.String("list-by-directory", "", "List templates under the directory relative to -path")
+	authorAvgSeverity = flag.Bool("author-avg-severity", false, "Show the average severity score of each author")
+	minTags           = flag.Int("min-tags", 0, "Show templates with fewer tags than this")
+	lintReport        = flag.Bool("lint-report", false, "Show every missing field lint issue with its level (error, warning, info), exits with status 1 on errors")
+	excessiveTags     = flag.Bool("check-excessive-tags", false, "Show templates with more tags than -max-tags-per-template")
+	maxTagsPerTpl     = flag.Int("max-tags-per-template", 10, "Maximum number of tags per template used by -check-excessive-tags")
+	maxAuthorsPerTpl  = flag.Int("max-authors-per-template", 0, "Show templates listing more authors than this")
+	maxTags           = flag.Int("max-tags", 0, "Show templates with more tags than this")
+	authorReportJSON  = flag.String("author-report-json", "", "File to write the enriched author leaderboard json to")
+	releaseNotes      = flag.Bool("generate-release-notes-md", false, "Generate markdown release notes from the -ta additions")
+	crossRepoIDs      = flag.String("cross-repo-ids", "", "URL of a plain text template id list to check for conflicting ids")
+	mitreMapping      = flag.Bool("mitre-mapping", false, "Show the MITRE ATT&CK techniques tagged by every template as json")
+	owaspStats        = flag.Bool("owasp-stats", false, "Show OWASP Top 10 Category Data inferred from tags")
+	tagPercentile     = flag.Bool("tag-frequency-percentile", false, "Show the frequency percentile of the -percentile-tag tag")
+	percentileTag     = flag.String("percentile-tag", "", "Tag used by -tag-frequency-percentile")
+	tagNetworkGraph   = flag.Bool("tag-network-graph", false, "Generate the tag co-occurrence network json for d3.js or gephi")
+	tagEntropy        = flag.Bool("tag-entropy", false, "Show the shannon entropy of the tag frequency distribution")
+	networkProtocols  = flag.Bool("network-protocols", false, "Show the number of network templates per protocol derived from their ports")
+	topTagsByType     = flag.Bool("top-tags-by-type", false, "Show the top tags of each template type")
+	typeByAuthor      = flag.Bool("template-type-by-author", false, "Show the top authors of each template type")
+	productFilter     = flag.String("find-templates-by-product", "", "List templates mentioning the product in their name, tags or description")
+	commitHistory     = flag.Bool("author-commit-history", false, "Show the monthly git commit history of the top authors")
+	authorJoinDate    = flag.Bool("author-join-date", false, "Show the first git contribution and template rate of every author")
+	authorDiffSize    = flag.Bool("author-pr-size-estimate", false, "Show the average template lines added per commit of every author")
+	authorPeriodTable = flag.Bool("author-stats-comparison-table", false, "Compare the templates modified by every author in -compare-period1 and -compare-period2")
+	comparePeriod1    = flag.String("compare-period1", "", "First YYYY-MM-DD:YYYY-MM-DD period of -author-stats-comparison-table")
+	comparePeriod2    = flag.String("compare-period2", "", "Second YYYY-MM-DD:YYYY-MM-DD period of -author-stats-comparison-table")
+	authorStatsGraph  = flag.Bool("author-stats-graph", false, "Generate a svg chart of the monthly templates added by the top authors")
+	authorNames       = flag.String("author-filter", "", "Comma separated authors used by -author-commit-history")
+	checkWorkflowRefs = flag.Bool("check-workflow-refs", false, "Show workflow references to templates which do not exist")
+	checkSelfRef      = flag.Bool("check-self-referential", false, "Show templates referencing their own repository file")
+	checkCVEReference = flag.Bool("check-cve-reference", false, "Show CVE templates without a reference to their CVE id")
+	authorsNoCve      = flag.Bool("authors-no-cve", false, "Show authors who never contributed a CVE template")
+	directoryTree     = flag.Bool("directory-stats-tree", false, "Show the template count per directory as an indented tree")
+	pathDepth         = flag.Int("path-depth", 0, "Maximum directory depth shown by -directory-stats-tree (0 for unlimited)")
+	anomalyDetection  = flag.Bool("anomaly-detection", false, "Show templates whose tag count, description length or reference count is more than 2 standard deviations from the mean")
+	directoryHealth   = flag.Bool("directory-health-score", false, "Show the mean template health score per directory")
+	freshnessScore    = flag.Bool("template-freshness-score", false, "Show the ratio of templates modified in the last 90 days per directory")
+	cvssGrades        = flag.Bool("cvss-grade-distribution", false, "Show CVSS Grade Data")
+	cvssAlignment     = flag.Bool("check-cvss-alignment", false, "Show templates whose severity differs from the CVSS v3 rating of their score")
+	cvssMismatch      = flag.Bool("cvss-severity-mismatch", false, "Show templates whose CVSS grade contradicts their severity")
+	protocolMismatch  = flag.Bool("check-protocol-mismatch", false, "Show templates with a severity unusual for their protocol")
+	protocolConfig    = flag.String("protocol-expectations", "", "Yaml file of expected severities per protocol (default embedded)")
+	tagCloudCSV       = flag.String("tag-cloud-csv", "", "File to write the tag,weight csv of the tag cloud to")
+	coverageHeatmap   = flag.String("coverage-heatmap-csv", "", "File to write the tag x severity csv of the top 20 tags to")
+	excludeList       = flag.String("generate-exclude-list", "", "File to write the templates skipped by the filters to, usable with nuclei -exclude-templates")
+	tagStatsRaw       = flag.String("tag-stats-raw", "", "File to write the complete tag counts json to")
+	authorStatsRaw    = flag.String("author-stats-raw", "", "File to write the complete author counts json to")
+	severityStatsRaw  = flag.String("severity-stats-raw", "", "File to write the complete severity counts json to")
+	watchNewCves      = flag.Bool("watch-new-cves", false, "Watch the template directory and report new CVE templates")
+	authorRankChange  = flag.Bool("author-ranking-change-report", false, "Compare the author rankings of the -baseline and -current json outputs")
+	current           = flag.String("current", "", "Current json output file to compare against -baseline")
+	ageBySeverity     = flag.Bool("age-by-severity", false, "Show the average template age in days per severity")
+	schemaValidate    = flag.Bool("schema-validate", false, "Validate templates against a json schema")
+	schemaFile        = flag.String("schema", "", "Json schema file used by -schema-validate (default bundled)")
+	authorCacheFile   = flag.String("author-stats-cache", "", "Gob file caching author stats to only recompute changed templates")
+	generateSBOM      = flag.Bool("generate-sbom", false, "Generate a json inventory of all templates")
+	serve             = flag.Bool("serve", false, "Serve the stats as a json http api")
+	port              = flag.Int("port", 8080, "Port used by -serve")
+	showVersion       = flag.Bool("version", false, "Show the version of templates-stats")
+	expandTags        = flag.String("expand-compound-tags", "", "Separator splitting compound tags into components counted as expanded tags")
+	filterTag         = flag.String("filter-tag", "", "Comma separated tags the stats are restricted to")
+	excludeTag        = flag.String("exclude-tag", "", "Comma separated tags removed from the stats")
+	filterSeverity    = flag.String("filter-severity", "", "Comma separated severities the stats are restricted to")
+	checkDuplicates   = flag.Bool("check-duplicates", false, "Report template ids declared by more than one file")
+	progress          = flag.Bool("progress", false, "Show a progress bar of the parsed templates on stderr")
+	workers           = flag.Int("workers", runtime.NumCPU(), "Number of templates parsed concurrently")
+	helperPattern     = flag.String("helper-pattern", "", "Glob of helper files relative to -path excluded from the stats (e.g. helpers/*.yaml)")
+	detectCollisions  = flag.Bool("detect-collisions", false, "Show templates of a directory which may fire on the same target")
 	templateDirectory = flag.String("path", "", "Template Directory")
 )
 
-type Output struct {
-	Tags      PairList `json:"tags,omitempty"`
-	Authors   PairList `json:"authors,omitempty"`
-	Directory PairList `json:"directory,omitempty"`
-	Severity  PairList `json:"severity,omitempty"`
-	Types     PairList `json:"types,omitempty"`
+// normalizePath converts the separators of path to forward slashes when -normalize-paths is used
+func normalizePath(path string) string {
+	if *normalizePaths {
+		return filepath.ToSlash(path)
+	}
+	return path
 }
 
-func (o *Output) getMaxItemCount() int {
-	max := len(o.Tags)
-	if newMax := len(o.Authors); newMax > max {
-		max = newMax
-	}
-	if newMax := len(o.Directory); newMax > max {
-		max = newMax
-	}
-	if newMax := len(o.Severity); newMax > max {
-		max = newMax
-	}
-	if newMax := len(o.Types); newMax > max {
-		max = newMax
+// templateReferences returns the references of a template which can
+// either be a single string or a list of strings.
+func templateReferences(record templateRecord) []string {
+	var references []string
+	switch reference := record.Info["reference"].(type) {
+	case string:
+		if reference != "" {
+			references = append(references, reference)
+		}
+	case []interface{}:
+		for _, item := range reference {
+			if value := types.ToString(item); value != "" {
+				references = append(references, value)
+			}
+		}
 	}
-	return max
+	return references
 }
 
 type NonCveItem struct {
@@ -143,6 +251,14 @@ func (c CveList) Less(i, j int) bool {
 func main() {
 	flag.Parse()
 
+	if *strictJSON {
+		*jsonOutput = true
+	}
+	if *showVersion {
+		printVersion(os.Stdout)
+		return
+	}
+
 	if *templateDirectory == "" {
 		homedir, err := os.UserHomeDir()
 		if err != nil {
@@ -150,166 +266,165 @@ func main() {
 		}
 		*templateDirectory = filepath.Join(homedir, "nuclei-templates")
 	}
+	if *explainSeverityID != "" {
+		if err := printSeverityExplanation(*templateDirectory, *explainSeverityID, os.Stdout); err != nil {
+			log.Fatalf("Could not explain severity: %s\n", err)
+		}
+		return
+	}
+	if *showTemplateID != "" {
+		if err := printTemplateByID(*templateDirectory, *showTemplateID, os.Stdout); err != nil {
+			log.Fatalf("Could not show template: %s\n", err)
+		}
+		return
+	}
 	if *ta != "" {
 		if err := printTemplateAdditions(*ta); err != nil {
 			log.Fatalf("Could not print additions: %s\n", err)
 		}
 		return
 	}
+	if *authorRankChange {
+		if *baseline == "" || *current == "" {
+			log.Fatalf("-baseline and -current are required with -author-ranking-change-report\n")
+		}
+		if err := printAuthorRankingReport(*baseline, *current, os.Stdout); err != nil {
+			log.Fatalf("Could not compare author rankings: %s\n", err)
+		}
+		return
+	}
+	if *authorCacheFile != "" {
+		if err := printCachedAuthorStats(statsConfigFromFlags(), *authorCacheFile, os.Stdout); err != nil {
+			log.Fatalf("Could not compute cached author stats: %s\n", err)
+		}
+		return
+	}
+	if *watchNewCves {
+		if err := watchNewCVEs(*templateDirectory, os.Stdout); err != nil {
+			log.Fatalf("Could not watch new cves: %s\n", err)
+		}
+		return
+	}
+	if *serve {
+		if err := serveStats(statsConfigFromFlags(), *port); err != nil {
+			log.Fatalf("Could not serve stats: %s\n", err)
+		}
+		return
+	}
+	if *trackRenames {
+		if err := printRenamedTemplates(*templateDirectory, os.Stdout); err != nil {
+			log.Fatalf("Could not track renames: %s\n", err)
+		}
+		return
+	}
 	printTemplateStats()
 }
 
+// statsConfigFromFlags creates the stats configuration from the cli flags.
+// The template directory is made absolute so the reports relativizing the
+// record paths use the same root as the catalog.
+func statsConfigFromFlags() StatsConfig {
+	directory, err := filepath.Abs(*templateDirectory)
+	if err != nil {
+		log.Fatalf("Could not resolve template directory: %s\n", err)
+	}
+	return StatsConfig{
+		TemplateDirectory: directory,
+		TopN:              *count,
+		Verbose:           *verbose,
+		NormalizePaths:    *normalizePaths,
+		HelperPattern:     *helperPattern,
+		Workers:           *workers,
+		Progress:          *progress,
+		CheckDuplicates:   *checkDuplicates,
+		StableTies:        *strictJSON,
+		FilterSeverities:  filterValues(*filterSeverity),
+		FilterTags:        filterValues(*filterTag),
+		ExcludeTags:       filterValues(*excludeTag),
+		TagSeparator:      *expandTags,
+		Tags:              *tagsFilter,
+		Authors:           *authorFilter,
+		Directory:         *directoryFilter,
+		Severity:          *severityFilter,
+		Types:             *typesFilter,
+		CveAuthors:        *cveAuthorsFilter,
+		ExtractorTypes:    *extractorTypes,
+		QualityGrades:     *qualityFilter,
+		OWASP:             *owaspStats,
+		CVSSGrades:        *cvssGrades,
+	}
+}
+
 func printTemplateStats() {
-	catalogClient := disk.NewCatalog(*templateDirectory)
-	includedTemplates, err := catalogClient.GetTemplatePath(*templateDirectory)
+	cfg := statsConfigFromFlags()
+	stats, err := templatestats.Collect(cfg)
 	if err != nil {
 		log.Fatal(err)
 	}
+	records := stats.Records
+	tagMap, authorMap, severityMap := stats.Tags, stats.Authors, stats.Severity
 
-	tagMap := make(map[string]int)
-	authorMap := make(map[string]int)
-	severityMap := make(map[string]int)
-	directoryMap := make(map[string]int)
-	typesMap := make(map[string]int)
-	var cveList CveList
-	var nonCveList NonCveList
-	for _, template := range includedTemplates {
-		templateRelativePath := stringsutil.TrimPrefixAny(template, *templateDirectory, "/", "\\")
-
-		var firstItem string
-		if !stringsutil.ContainsAny(templateRelativePath, "/", "\\") {
-			firstItem = templateRelativePath
-		} else {
-			firstItem = templateRelativePath[:strings.IndexAny(templateRelativePath, "/\\")]
-		}
-
-		directoryMap[firstItem]++
-
-		if !stringsutil.EqualFoldAny(filepath.Ext(template), ".yaml") {
-			if *verbose {
-				fmt.Printf("[ignored] %s\n", template)
-			}
-			continue
+	if *authorIndex != "" {
+		if err := writeCoverageMap(*authorIndex, buildAuthorIndex(records)); err != nil {
+			log.Fatalf("Could not write author index: %s\n", err)
 		}
-
-		f, err := os.Open(template)
-		if err != nil {
-			log.Printf("Could not read %s: %s\n", template, err)
-			continue
-		}
-		data := make(map[string]interface{})
-		if err := yaml.NewDecoder(f).Decode(&data); err != nil {
-			f.Close()
-			log.Printf("Could not parse %s: %s\n", template, err)
-			continue
-		}
-		f.Close()
-		id, ok := data["id"]
-		if !ok {
-			continue
-		}
-		info := data["info"]
-		if info == nil {
-			continue
-		}
-		infoMap := info.(map[interface{}]interface{})
-
-		if *listCvesInReverse {
-			name := infoMap["name"]
-			author := infoMap["author"]
-			severity := infoMap["severity"]
-			if strings.HasPrefix(fmt.Sprintf("%v", id), "CVE-") {
-				cveList = append(cveList, CveItem{CveID: fmt.Sprintf("%v", id), Name: fmt.Sprintf("%v", name), Author: fmt.Sprintf("%v", author), Severity: fmt.Sprintf("%v", severity)})
-			} else {
-				nonCveList = append(nonCveList, NonCveItem{Id: fmt.Sprintf("%v", id), Name: fmt.Sprintf("%v", name), Author: fmt.Sprintf("%v", author), Severity: fmt.Sprintf("%v", severity)})
-			}
-			continue
+	}
+	if *tagIndex != "" {
+		if err := writeCoverageMap(*tagIndex, buildTagIndex(records, *count)); err != nil {
+			log.Fatalf("Could not write tag index: %s\n", err)
 		}
-
-		tags := infoMap["tags"]
-		if tags == nil {
-			if *verbose {
-				log.Printf("[lint] No tags found for template %s\n", template)
-			}
+	}
+	if *severityIndex != "" {
+		if err := writeCoverageMap(*severityIndex, buildSeverityIndex(records)); err != nil {
+			log.Fatalf("Could not write severity index: %s\n", err)
 		}
-		description := infoMap["description"]
-		if description == nil {
-			if *verbose {
-				log.Printf("[lint] No description found for template %s\n", template)
-			}
+	}
+	if *tagCloudCSV != "" {
+		if err := writeTagCloudCSV(*tagCloudCSV, tagMap); err != nil {
+			log.Fatalf("Could not write tag cloud csv: %s\n", err)
 		}
-		reference := infoMap["reference"]
-		if reference == nil {
-			if *verbose {
-				log.Printf("[lint] No reference found for template %s\n", template)
-			}
+	}
+	if *excludeList != "" {
+		if err := writeExcludeList(*excludeList, stats.Skipped); err != nil {
+			log.Fatalf("Could not write exclude list: %s\n", err)
 		}
-		tagsString := types.ToString(tags)
-
-		individualTags := strings.Split(tagsString, ",")
-		for _, tag := range individualTags {
-			count, ok := tagMap[tag]
-			if !ok {
-				tagMap[tag] = 1
-			} else {
-				tagMap[tag] = count + 1
-			}
+	}
+	if *coverageHeatmap != "" {
+		if err := writeCoverageHeatmapCSV(*coverageHeatmap, records, tagMap); err != nil {
+			log.Fatalf("Could not write coverage heatmap csv: %s\n", err)
 		}
-
-		author, ok := infoMap["author"]
-		if !ok {
-			log.Printf("[lint] no author found for template %s\n", template)
+	}
+	if *tagStatsRaw != "" {
+		if err := writeRawStats(*tagStatsRaw, tagMap); err != nil {
+			log.Fatalf("Could not write raw tag stats: %s\n", err)
 		}
-		authorStr := types.ToString(author)
-
-		severity, ok := infoMap["severity"]
-		if ok {
-			severityStr := strings.ToLower(types.ToString(severity))
-
-			count, ok := severityMap[severityStr]
-			if !ok {
-				severityMap[severityStr] = 1
-			} else {
-				severityMap[severityStr] = count + 1
-			}
+	}
+	if *authorStatsRaw != "" {
+		if err := writeRawStats(*authorStatsRaw, authorMap); err != nil {
+			log.Fatalf("Could not write raw author stats: %s\n", err)
 		}
-
-		for _, author := range explodeCommaSeparatedField(authorStr) {
-			count, ok := authorMap[author]
-			if !ok {
-				authorMap[author] = 1
-			} else {
-				authorMap[author] = count + 1
-			}
+	}
+	if *severityStatsRaw != "" {
+		if err := writeRawStats(*severityStatsRaw, severityMap); err != nil {
+			log.Fatalf("Could not write raw severity stats: %s\n", err)
 		}
-
-		if _, ok := data["requests"]; ok {
-			if count, ok := typesMap["http"]; !ok {
-				typesMap["http"] = 1
-			} else {
-				typesMap["http"] = count + 1
-			}
+	}
+	if *authorReportJSON != "" {
+		if err := writeAuthorReport(*authorReportJSON, records, *count); err != nil {
+			log.Fatalf("Could not write author report: %s\n", err)
 		}
-		if _, ok := data["dns"]; ok {
-			if count, ok := typesMap["dns"]; !ok {
-				typesMap["dns"] = 1
-			} else {
-				typesMap["dns"] = count + 1
-			}
+	}
+	if *watchNewAuthors {
+		if *watchAuthorsState == "" {
+			log.Fatalf("-watch-authors-state is required with -watch-authors\n")
 		}
-		if _, ok := data["network"]; ok {
-			if count, ok := typesMap["network"]; !ok {
-				typesMap["network"] = 1
-			} else {
-				typesMap["network"] = count + 1
-			}
+		if err := watchAuthors(*watchAuthorsState, *webhookURL, authorMap); err != nil {
+			log.Fatalf("Could not watch authors: %s\n", err)
 		}
-		if _, ok := data["file"]; ok {
-			if count, ok := typesMap["file"]; !ok {
-				typesMap["file"] = 1
-			} else {
-				typesMap["file"] = count + 1
-			}
+	}
+	if *pathReport != "" {
+		if err := writeTemplatePathReport(*pathReport, records); err != nil {
+			log.Fatalf("Could not write template path report: %s\n", err)
 		}
 	}
 
@@ -324,7 +439,295 @@ func printTemplateStats() {
 		resultWriter = os.Stdout
 	}
 
-	if len(cveList) > 0 || len(nonCveList) > 0 {
+	if *authorSinceFirst {
+		printAuthorGrowth(records, resultWriter)
+		return
+	}
+	if *checkRefsTLS {
+		printTLSErrors(records, *insecureRefs, resultWriter)
+		return
+	}
+	if *verifyCveIDs {
+		printInvalidCVEIDs(records, resultWriter)
+		return
+	}
+	if *listInteractsh {
+		printInteractshTemplates(records, resultWriter)
+		return
+	}
+	if *schemaValidate {
+		if err := printSchemaViolations(records, *schemaFile, resultWriter); err != nil {
+			log.Fatalf("Could not validate schema: %s\n", err)
+		}
+		return
+	}
+	if *validateStructure {
+		printStructuralViolations(records, resultWriter)
+		return
+	}
+	if *commitHistory {
+		if err := printAuthorCommitHistory(cfg.TemplateDirectory, authorMap, *authorNames, resultWriter); err != nil {
+			log.Fatalf("Could not get author commit history: %s\n", err)
+		}
+		return
+	}
+	if *authorJoinDate {
+		if err := printAuthorJoinDates(cfg.TemplateDirectory, authorMap, *authorNames, resultWriter); err != nil {
+			log.Fatalf("Could not get author join dates: %s\n", err)
+		}
+		return
+	}
+	if *authorDiffSize {
+		if err := printAuthorDiffSizes(cfg.TemplateDirectory, authorMap, *authorNames, resultWriter); err != nil {
+			log.Fatalf("Could not estimate author diff sizes: %s\n", err)
+		}
+		return
+	}
+	if *authorPeriodTable {
+		if *comparePeriod1 == "" || *comparePeriod2 == "" {
+			log.Fatalf("-compare-period1 and -compare-period2 are required with -author-stats-comparison-table\n")
+		}
+		if err := printAuthorPeriodComparison(records, *comparePeriod1, *comparePeriod2, resultWriter); err != nil {
+			log.Fatalf("Could not compare author periods: %s\n", err)
+		}
+		return
+	}
+	if *authorStatsGraph {
+		if err := printAuthorStatsGraph(cfg.TemplateDirectory, authorMap, resultWriter); err != nil {
+			log.Fatalf("Could not generate author stats graph: %s\n", err)
+		}
+		return
+	}
+	if *crossRepoIDs != "" {
+		if err := printCrossRepoConflicts(records, *crossRepoIDs, resultWriter); err != nil {
+			log.Fatalf("Could not check cross repo ids: %s\n", err)
+		}
+		return
+	}
+	if *tagNetworkGraph {
+		if err := writeTagNetwork(records, *count, resultWriter); err != nil {
+			log.Fatalf("Could not write tag network graph: %s\n", err)
+		}
+		return
+	}
+	if *tagEntropy {
+		printTagEntropy(tagMap, resultWriter)
+		return
+	}
+	if *tagPercentile {
+		if *percentileTag == "" {
+			log.Fatalf("-percentile-tag is required with -tag-frequency-percentile\n")
+		}
+		if err := printTagPercentile(tagMap, *percentileTag, resultWriter); err != nil {
+			log.Fatalf("Could not compute tag percentile: %s\n", err)
+		}
+		return
+	}
+	if *protocolMismatch {
+		if err := printProtocolMismatches(records, *protocolConfig, resultWriter); err != nil {
+			log.Fatalf("Could not check protocol mismatch: %s\n", err)
+		}
+		return
+	}
+	if *cvssAlignment {
+		printAlignmentViolations(records, resultWriter)
+		return
+	}
+	if *cvssMismatch {
+		printSeverityMismatches(records, resultWriter)
+		return
+	}
+	if *checkWorkflowRefs {
+		printBrokenWorkflowRefs(records, cfg.TemplateDirectory, resultWriter)
+		return
+	}
+	if *checkSelfRef {
+		printSelfReferences(records, cfg.TemplateDirectory, resultWriter)
+		return
+	}
+	if *checkCVEReference {
+		printMissingCVEReferences(records, resultWriter)
+		return
+	}
+	if *maxAuthorsPerTpl > 0 {
+		printExcessiveAuthors(records, *maxAuthorsPerTpl, resultWriter)
+		return
+	}
+	if *lintReport {
+		if errorCount := printLintReport(stats.LintIssues, records, *maxTagsPerTpl, resultWriter); errorCount > 0 {
+			log.Fatalf("Found %d lint errors\n", errorCount)
+		}
+		return
+	}
+	if *excessiveTags {
+		printExcessiveTags(records, *maxTagsPerTpl, resultWriter)
+		return
+	}
+	if *minTags > 0 || *maxTags > 0 {
+		printTagCountViolations(records, resultWriter)
+		return
+	}
+	if *mitreMapping {
+		if err := printMitreMapping(records, resultWriter); err != nil {
+			log.Fatalf("Could not generate mitre mapping: %s\n", err)
+		}
+		return
+	}
+	if *networkProtocols {
+		printNetworkProtocols(records, resultWriter)
+		return
+	}
+	if *topTagsByType {
+		printTypeTags(stats.TypeTags, resultWriter)
+		return
+	}
+	if *typeByAuthor {
+		printTypeAuthors(records, resultWriter)
+		return
+	}
+	if *authorAvgSeverity {
+		printAuthorSeverity(records, resultWriter)
+		return
+	}
+	if *listByDirectory != "" {
+		printTemplatesInDirectory(records, cfg.TemplateDirectory, *listByDirectory, resultWriter)
+		return
+	}
+	if *productFilter != "" {
+		printTemplatesByProduct(records, *productFilter, resultWriter)
+		return
+	}
+	if *detectCollisions {
+		printPotentialCollisions(records, resultWriter)
+		return
+	}
+	if *listMultiType {
+		printMultiTypeTemplates(records, resultWriter)
+		return
+	}
+	if *generateSBOM {
+		if err := writeTemplateInventory(records, resultWriter); err != nil {
+			log.Fatalf("Could not write template inventory: %s\n", err)
+		}
+		return
+	}
+	if *nucleiConfigGen {
+		config := buildNucleiConfig(tagMap, severityMap, *count, templatestats.ExplodeCommaSeparatedField(*configSeverity))
+		if err := writeNucleiConfig(config, resultWriter); err != nil {
+			log.Fatalf("Could not write nuclei config: %s\n", err)
+		}
+		return
+	}
+	if *minReferences > 0 || *maxReferences > 0 {
+		printReferenceCountViolations(records, resultWriter)
+		return
+	}
+	if *severityTrend {
+		printSeverityTrend(records, resultWriter)
+		return
+	}
+	if *ageBySeverity {
+		printSeverityAge(records, resultWriter)
+		return
+	}
+	if *directoryTree {
+		printDirectoryTree(records, cfg.TemplateDirectory, *pathDepth, resultWriter)
+		return
+	}
+	if *anomalyDetection {
+		printAnomalousTemplates(records, resultWriter)
+		return
+	}
+	if *directoryHealth {
+		printDirectoryHealth(records, cfg.TemplateDirectory, resultWriter)
+		return
+	}
+	if *freshnessScore {
+		printDirectoryFreshness(records, cfg.TemplateDirectory, resultWriter)
+		return
+	}
+	if *checkAuthorFormat {
+		if err := printAuthorFormatViolations(records, *authorFormat, resultWriter); err != nil {
+			log.Fatalf("Could not check author format: %s\n", err)
+		}
+		return
+	}
+	if *descriptionStats {
+		printDescriptionStats(records, resultWriter)
+		return
+	}
+	if *authorRatio {
+		printAuthorTemplateRatio(authorMap, len(records), resultWriter)
+		return
+	}
+	if *cveCoverage {
+		if err := printCVECoverage(records, *cveYear, resultWriter); err != nil {
+			log.Fatalf("Could not compute cve coverage: %s\n", err)
+		}
+		return
+	}
+	if *authorOverlap {
+		printAuthorOverlap(records, resultWriter)
+		return
+	}
+	if *authorsHybrid {
+		printHybridAuthors(records, resultWriter)
+		return
+	}
+	if *authorsNoCve {
+		printNoCveAuthors(records, resultWriter)
+		return
+	}
+	if *simulateTop {
+		printCoverageTable([]statCategory{
+			{Name: "tags", Counts: tagMap},
+			{Name: "authors", Counts: authorMap},
+			{Name: "directory", Counts: stats.Directory},
+			{Name: "severity", Counts: severityMap},
+			{Name: "types", Counts: stats.Types},
+		}, resultWriter)
+		return
+	}
+	if *formatVersion != "" {
+		if err := printFormatVersionViolations(records, *formatVersion, resultWriter); err != nil {
+			log.Fatalf("Could not check format versions: %s\n", err)
+		}
+		return
+	}
+	if *compatCheck {
+		if err := printDeprecationWarnings(records, resultWriter); err != nil {
+			log.Fatalf("Could not check compatibility: %s\n", err)
+		}
+		return
+	}
+	if *checkMissingInfo {
+		printMissingInfoFields(records, resultWriter)
+		return
+	}
+	if *severityPctChange {
+		if *baseline == "" {
+			log.Fatalf("-baseline is required with -severity-pct-change\n")
+		}
+		if err := printSeverityChanges(*baseline, severityMap, resultWriter); err != nil {
+			log.Fatalf("Could not compare severity: %s\n", err)
+		}
+		return
+	}
+
+	if *listCvesInReverse {
+		var cveList CveList
+		var nonCveList NonCveList
+		for _, record := range records {
+			name := record.Info["name"]
+			author := record.Info["author"]
+			severity := record.Info["severity"]
+			if strings.HasPrefix(record.ID, "CVE-") {
+				cveList = append(cveList, CveItem{CveID: record.ID, Name: fmt.Sprintf("%v", name), Author: fmt.Sprintf("%v", author), Severity: fmt.Sprintf("%v", severity)})
+			} else {
+				nonCveList = append(nonCveList, NonCveItem{Id: record.ID, Name: fmt.Sprintf("%v", name), Author: fmt.Sprintf("%v", author), Severity: fmt.Sprintf("%v", severity)})
+			}
+		}
+
 		sort.Sort(cveList)
 		hasTopFilter := *count > 0
 		if hasTopFilter && len(cveList) > *count {
@@ -345,95 +748,285 @@ func printTemplateStats() {
 		os.Exit(0)
 	}
 
-	output := &Output{}
-	if *tagsFilter || *authorFilter || *directoryFilter || *typesFilter || *severityFilter {
-		// we have a filter. only run the asked one.
-		if *tagsFilter {
-			output.Tags = newPairListFromMap(tagMap, *count)
-		}
-		if *authorFilter {
-			output.Authors = newPairListFromMap(authorMap, *count)
+	if *format == "grafana" {
+		topAuthors := *count
+		if topAuthors == 0 {
+			topAuthors = grafanaTopAuthors
 		}
-		if *directoryFilter {
-			output.Directory = newPairListFromMap(directoryMap, *count)
+		grafanaOutput := &Output{
+			Tags:     newPairListFromMap(tagMap, grafanaTopTags),
+			Authors:  newPairListFromMap(authorMap, topAuthors),
+			Severity: newPairListFromMap(severityMap, 0),
 		}
-		if *typesFilter {
-			output.Types = newPairListFromMap(typesMap, *count)
+		if err := renderGrafana(grafanaOutput, len(records), resultWriter); err != nil {
+			log.Fatalf("Could not write grafana dashboard: %s\n", err)
 		}
-		if *severityFilter {
-			output.Severity = newPairListFromMap(severityMap, *count)
-		}
-	} else {
-		output.Tags = newPairListFromMap(tagMap, *count)
-		output.Authors = newPairListFromMap(authorMap, *count)
-		output.Directory = newPairListFromMap(directoryMap, *count)
-		output.Types = newPairListFromMap(typesMap, *count)
-		output.Severity = newPairListFromMap(severityMap, *count)
+		return
 	}
 
-	if *jsonOutput {
+	output := stats.Output(cfg)
+	if len(output.Duplicates) > 0 && !*jsonOutput {
+		printDuplicateIDs(output.Duplicates, os.Stderr)
+	}
+
+	switch {
+	case *format == "html-leaderboard":
+		if err := renderHTMLLeaderboard(records, *count, resultWriter); err != nil {
+			log.Fatalf("Could not write html leaderboard: %s\n", err)
+		}
+	case *format == "dot":
+		output.Directory = newPairListFromMap(stats.Directory, *count)
+		renderDOTSeverityDirectory(output, buildDirectorySeverityMap(records, cfg.TemplateDirectory), resultWriter)
+	case *format == "parquet":
+		if err := renderParquet(records, cfg.TemplateDirectory, resultWriter); err != nil {
+			log.Fatalf("Could not write parquet file: %s\n", err)
+		}
+	case *format == "yaml":
+		if err := yaml.NewEncoder(resultWriter).Encode(output); err != nil {
+			log.Fatalf("Could not encode yaml: %s\n", err)
+		}
+	case *format == "jsonlines":
+		if err := renderCategoryLines(output, resultWriter); err != nil {
+			log.Fatalf("Could not encode json lines: %s\n", err)
+		}
+	case *htmlOutput:
+		if err := renderHTML(output, resultWriter); err != nil {
+			log.Fatalf("Could not write html report: %s\n", err)
+		}
+	case *jsonLines:
+		if err := renderJSONLines(output, resultWriter); err != nil {
+			log.Fatalf("Could not encode json lines: %s\n", err)
+		}
+	case *csvDir != "":
+		if err := renderCSVDir(output, *csvDir); err != nil {
+			log.Fatalf("Could not write csv files: %s\n", err)
+		}
+	case *csvOutput:
+		if err := renderCSV(output, resultWriter); err != nil {
+			log.Fatalf("Could not encode csv: %s\n", err)
+		}
+	case *strictJSON:
+		if err := writeStrictJSON(output, resultWriter); err != nil {
+			log.Fatalf("Could not encode json: %s\n", err)
+		}
+	case *jsonOutput:
 		if err := json.NewEncoder(resultWriter).Encode(output); err != nil {
 			log.Fatalf("Could not encode json: %s\n", err)
 		}
-	} else {
-		renderMarkdown(output, resultWriter)
+	default:
+		colors := *format == "terminal256" || (*format == "" && *outputFile == "" && stdoutSupports256Colors())
+		renderMarkdown(output, colors, resultWriter)
 	}
 }
 
-func renderMarkdown(output *Output, writer io.Writer) {
-	maxItems := output.getMaxItemCount()
+// renderMarkdown writes the categories as a markdown table, the severity
+// column is colored with 256 color ANSI escapes when colors is set.
+func renderMarkdown(output *Output, colors bool, writer io.Writer) {
+	maxItems := output.MaxItemCount()
+	columns := output.Columns()
 
 	data := make([][]string, maxItems)
 	for i := range data {
-		data[i] = make([]string, 10)
+		data[i] = make([]string, len(columns)*2)
 	}
-	for i, tag := range output.Tags {
-		data[i][0] = tag.Key
-		data[i][1] = strconv.Itoa(tag.Value)
+	header := make([]string, 0, len(columns)*2)
+	for c, column := range columns {
+		header = append(header, column.Header, "Count")
+		for i, pair := range column.Pairs {
+			data[i][c*2] = pair.Key
+			if colors && column.Category == "severity" {
+				data[i][c*2] = colorSeverity(pair.Key)
+			}
+			data[i][c*2+1] = strconv.Itoa(pair.Value)
+		}
 	}
-	for i, tag := range output.Authors {
-		data[i][2] = tag.Key
-		data[i][3] = strconv.Itoa(tag.Value)
+	table := tablewriter.NewWriter(writer)
+	table.SetHeader(header)
+	setTableWidth(table, len(header))
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetCenterSeparator("|")
+	table.AppendBulk(data) // Add Bulk Data
+	table.Render()
+	if output.HelperCount > 0 {
+		fmt.Fprintf(writer, "\nExcluded %d helper files\n", output.HelperCount)
 	}
-	for i, tag := range output.Directory {
-		data[i][4] = tag.Key
-		data[i][5] = strconv.Itoa(tag.Value)
+}
+
+// categoryLine is a single category of the jsonlines output format
+type categoryLine struct {
+	Category string   `json:"category"`
+	Data     PairList `json:"data"`
+}
+
+// renderCategoryLines writes every populated category as its own json line
+func renderCategoryLines(output *Output, writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	for _, column := range output.Columns() {
+		if len(column.Pairs) == 0 {
+			continue
+		}
+		if err := encoder.Encode(categoryLine{Category: column.Category, Data: column.Pairs}); err != nil {
+			return err
+		}
 	}
-	for i, tag := range output.Severity {
-		data[i][6] = tag.Key
-		data[i][7] = strconv.Itoa(tag.Value)
+	return nil
+}
+
+// pairLine is a single pair of the -jsonl output
+type pairLine struct {
+	Category string `json:"category"`
+	Name     string `json:"name"`
+	Count    int    `json:"count"`
+}
+
+// renderJSONLines writes every pair of the populated categories as its own
+// json line, in the order of the table columns.
+func renderJSONLines(output *Output, writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	for _, column := range output.Columns() {
+		for _, pair := range column.Pairs {
+			if err := encoder.Encode(pairLine{Category: column.Category, Name: pair.Key, Count: pair.Value}); err != nil {
+				return err
+			}
+		}
 	}
-	for i, tag := range output.Types {
-		data[i][8] = tag.Key
-		data[i][9] = strconv.Itoa(tag.Value)
+	return nil
+}
+
+// writePairsCSV writes pairs as name,count csv rows below a header
+func writePairsCSV(writer *csv.Writer, pairs PairList) error {
+	if err := writer.Write([]string{"name", "count"}); err != nil {
+		return err
+	}
+	for _, pair := range pairs {
+		if err := writer.Write([]string{pair.Key, strconv.Itoa(pair.Value)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderCSV writes every populated category as a csv section starting with
+// the category name, sections are separated by a blank line.
+func renderCSV(output *Output, writer io.Writer) error {
+	csvWriter := csv.NewWriter(writer)
+	first := true
+	for _, column := range output.Columns() {
+		if len(column.Pairs) == 0 {
+			continue
+		}
+		if !first {
+			csvWriter.Flush()
+			if _, err := io.WriteString(writer, "\n"); err != nil {
+				return err
+			}
+		}
+		first = false
+		if err := csvWriter.Write([]string{column.Category}); err != nil {
+			return err
+		}
+		if err := writePairsCSV(csvWriter, column.Pairs); err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// renderCSVDir writes every populated category to its own <category>.csv file in directory
+func renderCSVDir(output *Output, directory string) error {
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return errors.Wrap(err, "could not create csv directory")
+	}
+	for _, column := range output.Columns() {
+		if len(column.Pairs) == 0 {
+			continue
+		}
+		file, err := os.Create(filepath.Join(directory, column.Category+".csv"))
+		if err != nil {
+			return errors.Wrap(err, "could not create csv file")
+		}
+		csvWriter := csv.NewWriter(file)
+		if err := writePairsCSV(csvWriter, column.Pairs); err != nil {
+			file.Close()
+			return errors.Wrap(err, "could not write csv file")
+		}
+		csvWriter.Flush()
+		if err := csvWriter.Error(); err != nil {
+			file.Close()
+			return errors.Wrap(err, "could not write csv file")
+		}
+		file.Close()
+	}
+	return nil
+}
+
+// writeStrictJSON writes value as json without html escaping. Map keys are
+// sorted by encoding/json and struct fields keep their declaration order so
+// the same value always results in the same bytes.
+func writeStrictJSON(value interface{}, writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(value)
+}
+
+// writeReport writes value as json when -json is used and rows as a table otherwise
+func writeReport(writer io.Writer, value interface{}, header []string, rows [][]string) {
+	if *jsonOutput {
+		if err := json.NewEncoder(writer).Encode(value); err != nil {
+			log.Fatalf("Could not encode json: %s\n", err)
+		}
+		return
 	}
+	renderTable(writer, header, rows)
+}
+
+func renderTable(writer io.Writer, header []string, rows [][]string) {
 	table := tablewriter.NewWriter(writer)
-	table.SetHeader([]string{"Tag", "Count", "Author", "Count", "Directory", "Count", "Severity", "Count", "Type", "Count"})
+	table.SetHeader(header)
+	setTableWidth(table, len(header))
 	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
 	table.SetCenterSeparator("|")
-	table.AppendBulk(data) // Add Bulk Data
+	table.AppendBulk(rows)
 	table.Render()
 }
 
-func printTemplateAdditions(additionFile string) error {
+// setTableWidth splits the -output-width between the table columns
+func setTableWidth(table *tablewriter.Table, columns int) {
+	if *outputWidth <= 0 || columns == 0 {
+		return
+	}
+	table.SetColWidth(*outputWidth / columns)
+}
+
+// readTemplateAdditions parses the templates listed in the addition file
+// which have an id, an info block and an author.
+func readTemplateAdditions(additionFile string) ([]templateRecord, error) {
 	f, err := os.Open(additionFile)
 	if err != nil {
-		return errors.Wrap(err, "could not open addition file")
+		return nil, errors.Wrap(err, "could not open addition file")
 	}
 	defer f.Close()
 
-	output, err := os.Create(*outputFile)
-	if err != nil {
-		return errors.Wrap(err, "could not open output file file")
+	var renamed map[string]string
+	if *trackRenames {
+		renames, err := gitRenames(*templateDirectory)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get renames")
+		}
+		renamed = renamedTargets(renames)
 	}
-	defer output.Close()
 
-	var cveList CveList
-	var nonCveList NonCveList
+	var records []templateRecord
 	scanner := bufio.NewScanner(f)
 	for scanner.Scan() {
 		text := scanner.Text()
 
+		if oldPath, ok := renamed[filepath.ToSlash(text)]; ok {
+			log.Printf("ignoring %s renamed from %s\n", text, oldPath)
+			continue
+		}
+
 		templatePath := filepath.Join(*templateDirectory, text)
 
 		if !stringsutil.EqualFoldAny(filepath.Ext(templatePath), ".yaml") {
@@ -465,25 +1058,47 @@ func printTemplateAdditions(additionFile string) error {
 			continue
 		}
 		infoMap := info.(map[interface{}]interface{})
-		author, ok := infoMap["author"]
-		if !ok {
+		if _, ok := infoMap["author"]; !ok {
 			log.Printf("no author found for template %s\n", text)
 			continue
 		}
-		authorStr := types.ToString(author)
+		records = append(records, templateRecord{Path: normalizePath(text), ID: types.ToString(id), Data: data, Info: infoMap})
+	}
+	return records, nil
+}
+
+func printTemplateAdditions(additionFile string) error {
+	records, err := readTemplateAdditions(additionFile)
+	if err != nil {
+		return err
+	}
+
+	output, err := os.Create(*outputFile)
+	if err != nil {
+		return errors.Wrap(err, "could not open output file file")
+	}
+	defer output.Close()
 
+	if *releaseNotes {
+		renderReleaseNotes(records, output)
+		return nil
+	}
+
+	var cveList CveList
+	var nonCveList NonCveList
+	for _, record := range records {
 		if *listCvesInReverse {
-			name := infoMap["name"]
-			author := infoMap["author"]
-			severity := infoMap["severity"]
-			if strings.HasPrefix(fmt.Sprintf("%v", id), "CVE-") {
-				cveList = append(cveList, CveItem{CveID: fmt.Sprintf("%v", id), Name: fmt.Sprintf("%v", name), Author: fmt.Sprintf("%v", author), Severity: fmt.Sprintf("%v", severity)})
+			name := record.Info["name"]
+			author := record.Info["author"]
+			severity := record.Info["severity"]
+			if strings.HasPrefix(record.ID, "CVE-") {
+				cveList = append(cveList, CveItem{CveID: record.ID, Name: fmt.Sprintf("%v", name), Author: fmt.Sprintf("%v", author), Severity: fmt.Sprintf("%v", severity)})
 			} else {
-				nonCveList = append(nonCveList, NonCveItem{Id: fmt.Sprintf("%v", id), Name: fmt.Sprintf("%v", name), Author: fmt.Sprintf("%v", author), Severity: fmt.Sprintf("%v", severity)})
+				nonCveList = append(nonCveList, NonCveItem{Id: record.ID, Name: fmt.Sprintf("%v", name), Author: fmt.Sprintf("%v", author), Severity: fmt.Sprintf("%v", severity)})
 			}
 			continue
 		}
-		_, _ = output.WriteString("- " + text + " by " + explodeAuthorsAndJoin(authorStr) + "\n")
+		_, _ = output.WriteString("- " + record.Path + " by " + explodeAuthorsAndJoin(types.ToString(record.Info["author"])) + "\n")
 	}
 
 	if len(cveList) > 0 {
@@ -528,17 +1143,14 @@ func explodeAuthorsAndJoin(author string) string {
 	return strings.Join(partValues, ",")
 }
 
-func explodeCommaSeparatedField(field string) []string {
-	if !strings.Contains(field, ",") {
-		return []string{strings.ToLower(field)}
-	}
-
-	parts := strings.Split(field, ",")
-	partValues := make([]string, 0, len(parts))
-	for _, part := range parts {
-		partValues = append(partValues, strings.ToLower(strings.TrimSpace(part)))
+// filterValues returns the normalized values of a comma separated filter
+// flag, an empty flag disables the filter.
+func filterValues(value string) []string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
 	}
-	return partValues
+	return templatestats.ExplodeCommaSeparatedField(value)
 }
 
 func formatCveItem(cveItem CveItem, fields []string) string {
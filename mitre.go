@@ -0,0 +1,61 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/projectdiscovery/templates-stats/pkg/templatestats"
+)
+
+//go:embed mitre-attack.json
+var mitreAttackData []byte
+
+// mitreTechniqueRegex matches tags which are MITRE ATT&CK technique ids
+var mitreTechniqueRegex = regexp.MustCompile(`^t\d{4}(\.\d{3})?$`)
+
+type MitreTechnique struct {
+	TechniqueID   string `json:"technique_id"`
+	TechniqueName string `json:"technique_name"`
+}
+
+// loadMitreTechniques returns the bundled technique names by technique id
+func loadMitreTechniques() (map[string]string, error) {
+	techniques := make(map[string]string)
+	if err := json.Unmarshal(mitreAttackData, &techniques); err != nil {
+		return nil, err
+	}
+	return techniques, nil
+}
+
+// buildMitreMapping returns the MITRE ATT&CK techniques tagged by every
+// template, techniques missing from the bundled list have an empty name.
+func buildMitreMapping(records []templateRecord, techniques map[string]string) map[string][]MitreTechnique {
+	mapping := make(map[string][]MitreTechnique)
+	for _, record := range records {
+		seen := make(map[string]struct{})
+		for _, tag := range templatestats.TemplateTags(record) {
+			tag = strings.ToLower(strings.TrimSpace(tag))
+			if !mitreTechniqueRegex.MatchString(tag) {
+				continue
+			}
+			id := strings.ToUpper(tag)
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			mapping[record.ID] = append(mapping[record.ID], MitreTechnique{TechniqueID: id, TechniqueName: techniques[id]})
+		}
+	}
+	return mapping
+}
+
+func printMitreMapping(records []templateRecord, writer io.Writer) error {
+	techniques, err := loadMitreTechniques()
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(writer).Encode(buildMitreMapping(records, techniques))
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// defaultConfigTags is the number of tags used when -top is not provided
+const defaultConfigTags = 10
+
+type nucleiConfig struct {
+	Tags     []string `yaml:"tags"`
+	Severity []string `yaml:"severity"`
+}
+
+// buildNucleiConfig creates a nuclei config scoped to the most common tags
+// and to the wanted severities present in the corpus.
+func buildNucleiConfig(tagMap, severityMap map[string]int, topTags int, severities []string) *nucleiConfig {
+	if topTags == 0 {
+		topTags = defaultConfigTags
+	}
+	config := &nucleiConfig{}
+	for _, pair := range newPairListFromMap(tagMap, 0) {
+		if len(config.Tags) == topTags {
+			break
+		}
+		if pair.Key != "" {
+			config.Tags = append(config.Tags, pair.Key)
+		}
+	}
+	for _, severity := range severities {
+		if _, ok := severityMap[severity]; ok {
+			config.Severity = append(config.Severity, severity)
+		}
+	}
+	return config
+}
+
+func writeNucleiConfig(config *nucleiConfig, writer io.Writer) error {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal nuclei config")
+	}
+	_, err = writer.Write(data)
+	return err
+}
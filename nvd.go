@@ -0,0 +1,277 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const nvdAPIURL = "https://services.nvd.nist.gov/rest/json/cves/2.0"
+
+var nvdClient = &http.Client{Timeout: 30 * time.Second}
+
+// nvdRateWindow is the window of the NVD API rate limits, NVD accepts
+// nvdPublicRequests requests per window without an api key and
+// nvdAPIKeyRequests with one.
+const (
+	nvdRateWindow     = 30 * time.Second
+	nvdPublicRequests = 5
+	nvdAPIKeyRequests = 50
+)
+
+// nvdMaxRetries is the number of retries of a rate limited nvd request,
+// the wait doubles from nvdRetryBackoff after every attempt.
+const (
+	nvdMaxRetries   = 3
+	nvdRetryBackoff = 6 * time.Second
+)
+
+// nvdRateLimiter spaces the requests evenly over the rate limit window
+type nvdRateLimiter struct {
+	mutex    sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// newNVDRateLimiter returns a limiter for the rate limit of the api key
+func newNVDRateLimiter(apiKey string) *nvdRateLimiter {
+	requests := nvdPublicRequests
+	if apiKey != "" {
+		requests = nvdAPIKeyRequests
+	}
+	return &nvdRateLimiter{interval: nvdRateWindow / time.Duration(requests)}
+}
+
+// wait blocks until the next request is allowed
+func (l *nvdRateLimiter) wait() {
+	l.mutex.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mutex.Unlock()
+
+	time.Sleep(delay)
+}
+
+var (
+	nvdLimiter     *nvdRateLimiter
+	nvdLimiterOnce sync.Once
+)
+
+// CVE id verification statuses
+const (
+	cveStatusInvalid    = "invalid"
+	cveStatusUnverified = "unverified"
+)
+
+type InvalidCVEID struct {
+	ID     string `json:"id"`
+	Path   string `json:"path"`
+	Status string `json:"status"`
+}
+
+type nvdResponse struct {
+	TotalResults    int `json:"totalResults"`
+	Vulnerabilities []struct {
+		CVE struct {
+			ID string `json:"id"`
+		} `json:"cve"`
+	} `json:"vulnerabilities"`
+}
+
+// errNVDRateLimited is returned when NVD keeps rejecting a request after
+// all retries
+var errNVDRateLimited = errors.New("nvd rate limit exceeded")
+
+// queryNVD performs a request against the NVD CVE API. Requests are rate
+// limited and retried with backoff when NVD answers with 403 or 429.
+func queryNVD(query url.Values) (*nvdResponse, error) {
+	nvdLimiterOnce.Do(func() { nvdLimiter = newNVDRateLimiter(*nvdAPIKey) })
+
+	backoff := nvdRetryBackoff
+	for attempt := 0; ; attempt++ {
+		nvdLimiter.wait()
+		response, err := queryNVDOnce(query)
+		if err != errNVDRateLimited || attempt == nvdMaxRetries {
+			return response, err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// queryNVDOnce performs a single request against the NVD CVE API
+func queryNVDOnce(query url.Values) (*nvdResponse, error) {
+	req, err := http.NewRequest(http.MethodGet, nvdAPIURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create nvd request")
+	}
+	if *nvdAPIKey != "" {
+		req.Header.Set("apiKey", *nvdAPIKey)
+	}
+	resp, err := nvdClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not query nvd")
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return &nvdResponse{}, nil
+	case http.StatusForbidden, http.StatusTooManyRequests:
+		return nil, errNVDRateLimited
+	default:
+		return nil, fmt.Errorf("unexpected nvd status code %d", resp.StatusCode)
+	}
+	var data nvdResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, errors.Wrap(err, "could not decode nvd response")
+	}
+	return &data, nil
+}
+
+// verifyCVEIDs checks every CVE template id against the NVD database and
+// returns the ones which could not be found as invalid and the ones which
+// could not be checked as unverified.
+func verifyCVEIDs(records []templateRecord, concurrency int) []InvalidCVEID {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	var (
+		mutex   sync.Mutex
+		wg      sync.WaitGroup
+		invalid []InvalidCVEID
+	)
+	semaphore := make(chan struct{}, concurrency)
+	for _, record := range records {
+		if !strings.HasPrefix(record.ID, "CVE-") {
+			continue
+		}
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(record templateRecord) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			status := cveStatusInvalid
+			response, err := queryNVD(url.Values{"cveId": []string{record.ID}})
+			if err != nil {
+				log.Printf("Could not verify %s: %s\n", record.ID, err)
+				status = cveStatusUnverified
+			} else if response.TotalResults > 0 {
+				return
+			}
+			mutex.Lock()
+			invalid = append(invalid, InvalidCVEID{ID: record.ID, Path: record.Path, Status: status})
+			mutex.Unlock()
+		}(record)
+	}
+	wg.Wait()
+
+	sort.Slice(invalid, func(i, j int) bool { return invalid[i].ID < invalid[j].ID })
+	return invalid
+}
+
+func printInvalidCVEIDs(records []templateRecord, writer io.Writer) {
+	invalid := verifyCVEIDs(records, *nvdConcurrency)
+
+	rows := make([][]string, 0, len(invalid))
+	for _, item := range invalid {
+		rows = append(rows, []string{item.ID, item.Path, item.Status})
+	}
+	writeReport(writer, invalid, []string{"CVE ID", "Path", "Status"}, rows)
+}
+
+// nvdMaxDateRange is the maximum publication date range accepted by the NVD API
+const nvdMaxDateRange = 120 * 24 * time.Hour
+
+// nvdMaxResultsPerPage is the maximum page size of the NVD API
+const nvdMaxResultsPerPage = 2000
+
+const nvdDateFormat = "2006-01-02T15:04:05.000"
+
+type CVECoverage struct {
+	Year          int     `json:"year"`
+	NvdTotal      int     `json:"nvd_total"`
+	TemplateCount int     `json:"template_count"`
+	CoveragePct   float64 `json:"coverage_pct"`
+}
+
+// fetchNVDCVEIDs returns the ids of the CVEs published by NVD in a year. The
+// year is split into ranges as NVD limits the size of the date range.
+func fetchNVDCVEIDs(year int) (map[string]struct{}, error) {
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(1, 0, 0)
+
+	ids := make(map[string]struct{})
+	for rangeStart := start; rangeStart.Before(end); rangeStart = rangeStart.Add(nvdMaxDateRange) {
+		rangeEnd := rangeStart.Add(nvdMaxDateRange)
+		if rangeEnd.After(end) {
+			rangeEnd = end
+		}
+		for startIndex := 0; ; startIndex += nvdMaxResultsPerPage {
+			response, err := queryNVD(url.Values{
+				"pubStartDate":   []string{rangeStart.Format(nvdDateFormat)},
+				"pubEndDate":     []string{rangeEnd.Add(-time.Millisecond).Format(nvdDateFormat)},
+				"resultsPerPage": []string{strconv.Itoa(nvdMaxResultsPerPage)},
+				"startIndex":     []string{strconv.Itoa(startIndex)},
+			})
+			if err != nil {
+				return nil, err
+			}
+			for _, vulnerability := range response.Vulnerabilities {
+				ids[strings.ToUpper(vulnerability.CVE.ID)] = struct{}{}
+			}
+			if len(response.Vulnerabilities) == 0 || startIndex+nvdMaxResultsPerPage >= response.TotalResults {
+				break
+			}
+		}
+	}
+	return ids, nil
+}
+
+// computeCVECoverage compares the CVEs published by NVD in a year with the
+// CVE templates of these CVEs. Templates are bucketed by the NVD publication
+// date as CVE ids reserved in a year are often published in later years.
+func computeCVECoverage(records []templateRecord, year int) (*CVECoverage, error) {
+	published, err := fetchNVDCVEIDs(year)
+	if err != nil {
+		return nil, err
+	}
+	coverage := &CVECoverage{Year: year, NvdTotal: len(published)}
+	for _, record := range records {
+		if _, ok := published[strings.ToUpper(record.ID)]; ok {
+			coverage.TemplateCount++
+		}
+	}
+	if coverage.NvdTotal > 0 {
+		coverage.CoveragePct = float64(coverage.TemplateCount) * 100 / float64(coverage.NvdTotal)
+	}
+	return coverage, nil
+}
+
+func printCVECoverage(records []templateRecord, year int, writer io.Writer) error {
+	coverage, err := computeCVECoverage(records, year)
+	if err != nil {
+		return err
+	}
+	rows := [][]string{{strconv.Itoa(coverage.Year), strconv.Itoa(coverage.NvdTotal), strconv.Itoa(coverage.TemplateCount), fmt.Sprintf("%.2f%%", coverage.CoveragePct)}}
+	writeReport(writer, coverage, []string{"Year", "NVD Total", "Templates", "Coverage"}, rows)
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+	"github.com/projectdiscovery/templates-stats/pkg/templatestats"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// parquetTemplate is the parquet schema of a template row
+type parquetTemplate struct {
+	ID          string   `parquet:"name=id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Path        string   `parquet:"name=path, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Directory   string   `parquet:"name=directory, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Name        string   `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Severity    string   `parquet:"name=severity, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Description string   `parquet:"name=description, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Authors     []string `parquet:"name=authors, type=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	Tags        []string `parquet:"name=tags, type=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	Types       []string `parquet:"name=types, type=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	References  []string `parquet:"name=references, type=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	ModTime     int64    `parquet:"name=mod_time, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+}
+
+func newParquetTemplate(record templateRecord, directory string) parquetTemplate {
+	var requestTypes []string
+	for _, key := range templateRequestTypes(record) {
+		requestTypes = append(requestTypes, templateTypeName(key))
+	}
+	var modTime int64
+	if !record.ModTime.IsZero() {
+		modTime = record.ModTime.UnixMilli()
+	}
+	return parquetTemplate{
+		ID:          record.ID,
+		Path:        normalizePath(record.Path),
+		Directory:   topLevelDirectory(directory, record.Path),
+		Name:        types.ToString(record.Info["name"]),
+		Severity:    strings.ToLower(types.ToString(record.Info["severity"])),
+		Description: strings.TrimSpace(types.ToString(record.Info["description"])),
+		Authors:     templateAuthors(record),
+		Tags:        templatestats.TemplateTags(record),
+		Types:       requestTypes,
+		References:  templateReferences(record),
+		ModTime:     modTime,
+	}
+}
+
+// renderParquet writes one parquet row per template
+func renderParquet(records []templateRecord, directory string, w io.Writer) error {
+	parquetWriter, err := writer.NewParquetWriterFromWriter(w, new(parquetTemplate), 1)
+	if err != nil {
+		return errors.Wrap(err, "could not create parquet writer")
+	}
+	parquetWriter.CompressionType = parquet.CompressionCodec_SNAPPY
+	for _, record := range records {
+		if err := parquetWriter.Write(newParquetTemplate(record, directory)); err != nil {
+			return errors.Wrap(err, "could not write parquet row")
+		}
+	}
+	if err := parquetWriter.WriteStop(); err != nil {
+		return errors.Wrap(err, "could not finish parquet file")
+	}
+	return nil
+}
@@ -0,0 +1,492 @@
+// Package templatestats computes statistics of the metadata of nuclei
+// templates independently of the templates-stats cli.
+package templatestats
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/catalog/disk"
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+	sliceutil "github.com/projectdiscovery/utils/slice"
+	stringsutil "github.com/projectdiscovery/utils/strings"
+	"github.com/schollz/progressbar/v3"
+	"gopkg.in/yaml.v2"
+)
+
+// StatsConfig configures a ComputeStats run independently of the cli flags
+type StatsConfig struct {
+	// TemplateDirectory is the directory templates are loaded from
+	TemplateDirectory string
+	// TopN limits every category to the N most common entries, 0 keeps all
+	TopN int
+	// Verbose logs ignored templates and lint warnings
+	Verbose bool
+	// NormalizePaths uses forward slashes in the template paths
+	NormalizePaths bool
+	// HelperPattern is a glob of helper files relative to TemplateDirectory
+	// which are counted separately and excluded from the stats
+	HelperPattern string
+	// TagSeparator splits compound tags into components counted in
+	// the additional expanded tags category
+	TagSeparator string
+	// FilterSeverities restricts the stats to templates of these severities
+	FilterSeverities []string
+	// FilterTags restricts the stats to templates carrying any of these tags
+	FilterTags []string
+	// ExcludeTags are left out of the tag counts, templates only carrying
+	// excluded tags are left out of the stats
+	ExcludeTags []string
+	// CheckDuplicates reports the template ids declared by several files
+	CheckDuplicates bool
+	// StableTies orders entries with equal counts by their key so the
+	// output does not depend on map iteration order
+	StableTies bool
+	// Progress shows a progress bar of the parsed templates on stderr
+	Progress bool
+	// Workers is the number of templates parsed concurrently, values below
+	// one use the number of CPUs
+	Workers int
+
+	// Categories to compute. When none are set the default categories
+	// (tags, authors, directory, types and severity) are computed.
+	Tags           bool
+	Authors        bool
+	Directory      bool
+	Severity       bool
+	Types          bool
+	CveAuthors     bool
+	ExtractorTypes bool
+	QualityGrades  bool
+	OWASP          bool
+	CVSSGrades     bool
+}
+
+// HasCategories reports whether any category was explicitly requested
+func (c StatsConfig) HasCategories() bool {
+	return c.Tags || c.Authors || c.Directory || c.Severity || c.Types || c.CveAuthors || c.ExtractorTypes || c.QualityGrades || c.OWASP || c.CVSSGrades
+}
+
+// HasFilters reports whether the templates are filtered
+func (c StatsConfig) HasFilters() bool {
+	return len(c.FilterSeverities) > 0 || len(c.FilterTags) > 0 || len(c.ExcludeTags) > 0
+}
+
+// ExcludesTag reports whether tag is excluded from the stats
+func (c StatsConfig) ExcludesTag(tag string) bool {
+	return sliceutil.Contains(c.ExcludeTags, strings.ToLower(strings.TrimSpace(tag)))
+}
+
+// IsHelper reports whether the file at relativePath matches the helper pattern
+func (c StatsConfig) IsHelper(relativePath string) bool {
+	if c.HelperPattern == "" {
+		return false
+	}
+	matched, _ := path.Match(c.HelperPattern, filepath.ToSlash(relativePath))
+	return matched
+}
+
+// IncludesTemplate reports whether a parsed template passes the filters
+func (c StatsConfig) IncludesTemplate(data map[string]interface{}) bool {
+	info, _ := data["info"].(map[interface{}]interface{})
+	if len(c.FilterSeverities) > 0 {
+		severity := strings.ToLower(strings.TrimSpace(types.ToString(info["severity"])))
+		if !sliceutil.Contains(c.FilterSeverities, severity) {
+			return false
+		}
+	}
+	if len(c.FilterTags) > 0 {
+		matched := false
+		for _, tag := range ExplodeCommaSeparatedField(types.ToString(info["tags"])) {
+			if sliceutil.Contains(c.FilterTags, strings.TrimSpace(tag)) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(c.ExcludeTags) > 0 {
+		tags := strings.Split(types.ToString(info["tags"]), ",")
+		excluded := 0
+		for _, tag := range tags {
+			if c.ExcludesTag(tag) {
+				excluded++
+			}
+		}
+		if excluded > 0 && excluded == len(tags) {
+			return false
+		}
+	}
+	return true
+}
+
+// Stats holds the raw counts and records collected from the templates
+type Stats struct {
+	Records        []Record
+	HelperCount    int
+	Tags           map[string]int
+	Authors        map[string]int
+	Severity       map[string]int
+	Directory      map[string]int
+	Types          map[string]int
+	CveAuthors     map[string]int
+	ExtractorTypes map[string]int
+	OWASP          map[string]int
+	// TypeTags counts the tags of every template type
+	TypeTags map[string]map[string]int
+	// Skipped holds the relative paths of the templates left out by the filters
+	Skipped []string
+	// ParseFailures holds the relative paths of the templates which could
+	// not be read or parsed
+	ParseFailures []string
+	// LintIssues are the missing fields found while parsing the templates
+	LintIssues []LintIssue
+}
+
+// ComputeStats scans the templates of cfg.TemplateDirectory and returns
+// the requested categories.
+func ComputeStats(cfg StatsConfig) (*Output, error) {
+	stats, err := Collect(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return stats.Output(cfg), nil
+}
+
+// parsedTemplate is the result of parsing a single template file
+type parsedTemplate struct {
+	Path         string
+	RelativePath string
+	FirstItem    string
+	Data         map[string]interface{}
+	ModTime      time.Time
+	Err          error
+}
+
+// parseTemplate reads and decodes the yaml of a template
+func parseTemplate(template *parsedTemplate) {
+	f, err := os.Open(template.Path)
+	if err != nil {
+		template.Err = errors.Wrapf(err, "could not read %s", template.Path)
+		return
+	}
+	defer f.Close()
+
+	data := make(map[string]interface{})
+	if err := yaml.NewDecoder(f).Decode(&data); err != nil {
+		template.Err = errors.Wrapf(err, "could not parse %s", template.Path)
+		return
+	}
+	template.Data = data
+	if stat, err := f.Stat(); err == nil {
+		template.ModTime = stat.ModTime()
+	}
+}
+
+// progressInterval is the number of parsed templates between progress bar updates
+const progressInterval = 100
+
+// parseTemplates parses the templates with a pool of workers, the results
+// are stored in place so their order is kept.
+func parseTemplates(templates []parsedTemplate, workers int, progress bool) {
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	var bar *progressbar.ProgressBar
+	if progress {
+		bar = progressbar.NewOptions(len(templates),
+			progressbar.OptionSetWriter(os.Stderr),
+			progressbar.OptionSetDescription("Processing templates"),
+			progressbar.OptionShowCount(),
+			progressbar.OptionOnCompletion(func() { fmt.Fprintln(os.Stderr) }),
+		)
+	}
+	var parsed int64
+	jobs := make(chan *parsedTemplate)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for template := range jobs {
+				parseTemplate(template)
+				if bar == nil {
+					continue
+				}
+				if done := atomic.AddInt64(&parsed, 1); done%progressInterval == 0 || int(done) == len(templates) {
+					_ = bar.Set(int(done))
+				}
+			}
+		}()
+	}
+	for i := range templates {
+		jobs <- &templates[i]
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// Collect parses every template and counts its metadata
+func Collect(cfg StatsConfig) (*Stats, error) {
+	// the catalog returns absolute paths, the directory has to match them
+	// for the relative paths of the templates.
+	directory, err := filepath.Abs(cfg.TemplateDirectory)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not resolve template directory")
+	}
+	cfg.TemplateDirectory = directory
+
+	catalogClient := disk.NewCatalog(cfg.TemplateDirectory)
+	includedTemplates, err := catalogClient.GetTemplatePath(cfg.TemplateDirectory)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get templates")
+	}
+	if cfg.HelperPattern != "" {
+		if _, err := path.Match(cfg.HelperPattern, ""); err != nil {
+			return nil, errors.Wrap(err, "invalid helper pattern")
+		}
+	}
+
+	stats := &Stats{
+		Tags:           make(map[string]int),
+		Authors:        make(map[string]int),
+		Severity:       make(map[string]int),
+		Directory:      make(map[string]int),
+		Types:          make(map[string]int),
+		CveAuthors:     make(map[string]int),
+		ExtractorTypes: make(map[string]int),
+		OWASP:          make(map[string]int),
+		TypeTags:       make(map[string]map[string]int),
+	}
+	var templates []parsedTemplate
+	for _, template := range includedTemplates {
+		templateRelativePath := stringsutil.TrimPrefixAny(template, cfg.TemplateDirectory, "/", "\\")
+
+		var firstItem string
+		if !stringsutil.ContainsAny(templateRelativePath, "/", "\\") {
+			firstItem = templateRelativePath
+		} else {
+			firstItem = templateRelativePath[:strings.IndexAny(templateRelativePath, "/\\")]
+		}
+
+		if cfg.IsHelper(templateRelativePath) {
+			stats.HelperCount++
+			continue
+		}
+
+		if !stringsutil.EqualFoldAny(filepath.Ext(template), ".yaml") {
+			if !cfg.HasFilters() {
+				stats.Directory[firstItem]++
+			}
+			if cfg.Verbose {
+				fmt.Printf("[ignored] %s\n", template)
+			}
+			continue
+		}
+		templates = append(templates, parsedTemplate{Path: template, RelativePath: filepath.ToSlash(templateRelativePath), FirstItem: firstItem})
+	}
+	parseTemplates(templates, cfg.Workers, cfg.Progress)
+
+	for _, parsed := range templates {
+		template, data := parsed.Path, parsed.Data
+		if parsed.Err == nil && !cfg.IncludesTemplate(data) {
+			stats.Skipped = append(stats.Skipped, parsed.RelativePath)
+			continue
+		}
+		stats.Directory[parsed.FirstItem]++
+		if parsed.Err != nil {
+			stats.ParseFailures = append(stats.ParseFailures, parsed.RelativePath)
+			log.Printf("%s\n", parsed.Err)
+			continue
+		}
+		recordPath := template
+		if cfg.NormalizePaths {
+			recordPath = filepath.ToSlash(recordPath)
+		}
+		id, ok := data["id"]
+		if !ok {
+			stats.LintIssues = append(stats.LintIssues, LintIssue{Path: recordPath, Field: "id", Level: LintError})
+			continue
+		}
+		info := data["info"]
+		if info == nil {
+			stats.LintIssues = append(stats.LintIssues, LintIssue{Path: recordPath, Field: "info", Level: LintError})
+			continue
+		}
+		infoMap := info.(map[interface{}]interface{})
+		stats.Records = append(stats.Records, Record{Path: recordPath, ID: types.ToString(id), Data: data, Info: infoMap, ModTime: parsed.ModTime})
+
+		tags := infoMap["tags"]
+		if tags == nil {
+			stats.LintIssues = append(stats.LintIssues, LintIssue{Path: recordPath, Field: "tags", Level: LintInfo})
+			if cfg.Verbose {
+				log.Printf("[lint] No tags found for template %s\n", template)
+			}
+		}
+		description := infoMap["description"]
+		if description == nil {
+			stats.LintIssues = append(stats.LintIssues, LintIssue{Path: recordPath, Field: "description", Level: LintWarning})
+			if cfg.Verbose {
+				log.Printf("[lint] No description found for template %s\n", template)
+			}
+		}
+		reference := infoMap["reference"]
+		if reference == nil {
+			stats.LintIssues = append(stats.LintIssues, LintIssue{Path: recordPath, Field: "reference", Level: LintWarning})
+			if cfg.Verbose {
+				log.Printf("[lint] No reference found for template %s\n", template)
+			}
+		}
+		tagsString := types.ToString(tags)
+
+		individualTags := strings.Split(tagsString, ",")
+		if len(cfg.ExcludeTags) > 0 {
+			kept := individualTags[:0]
+			for _, tag := range individualTags {
+				if !cfg.ExcludesTag(tag) {
+					kept = append(kept, tag)
+				}
+			}
+			individualTags = kept
+		}
+		for _, tag := range individualTags {
+			stats.Tags[tag]++
+		}
+		for _, category := range TemplateOWASPCategories(individualTags) {
+			stats.OWASP[category]++
+		}
+
+		author, ok := infoMap["author"]
+		if !ok {
+			stats.LintIssues = append(stats.LintIssues, LintIssue{Path: recordPath, Field: "author", Level: LintWarning})
+			log.Printf("[lint] no author found for template %s\n", template)
+		}
+		authorStr := types.ToString(author)
+
+		severity, ok := infoMap["severity"]
+		if ok {
+			severityStr := strings.ToLower(types.ToString(severity))
+			stats.Severity[severityStr]++
+		}
+
+		for _, author := range ExplodeCommaSeparatedField(authorStr) {
+			stats.Authors[author]++
+		}
+		if strings.HasPrefix(types.ToString(id), "CVE-") {
+			for _, author := range ExplodeCommaSeparatedField(authorStr) {
+				stats.CveAuthors[author]++
+			}
+		}
+
+		for _, extractorType := range TemplateExtractorTypes(data) {
+			stats.ExtractorTypes[extractorType]++
+		}
+
+		var templateTypes []string
+		if _, ok := data["requests"]; ok {
+			templateTypes = append(templateTypes, "http")
+		}
+		if _, ok := data["dns"]; ok {
+			templateTypes = append(templateTypes, "dns")
+		}
+		if _, ok := data["network"]; ok {
+			templateTypes = append(templateTypes, "network")
+		}
+		if _, ok := data["file"]; ok {
+			templateTypes = append(templateTypes, "file")
+		}
+		for _, templateType := range templateTypes {
+			stats.Types[templateType]++
+			if stats.TypeTags[templateType] == nil {
+				stats.TypeTags[templateType] = make(map[string]int)
+			}
+			for _, tag := range individualTags {
+				if tag != "" {
+					stats.TypeTags[templateType][tag]++
+				}
+			}
+		}
+	}
+	return stats, nil
+}
+
+// Output converts the collected counts into the categories requested by cfg
+func (s *Stats) Output(cfg StatsConfig) *Output {
+	output := &Output{HelperCount: s.HelperCount, explicitCategories: cfg.HasCategories()}
+	if cfg.TagSeparator != "" {
+		output.ExpandedTags = NewPairListFromMap(ExpandCompoundTags(s.Tags, cfg.TagSeparator), cfg.TopN, cfg.StableTies)
+	}
+	if cfg.CheckDuplicates {
+		output.Duplicates = FindDuplicateIDs(s.Records)
+	}
+	if !cfg.HasCategories() {
+		output.Tags = NewPairListFromMap(s.Tags, cfg.TopN, cfg.StableTies)
+		output.Authors = NewPairListFromMap(s.Authors, cfg.TopN, cfg.StableTies)
+		output.Directory = NewPairListFromMap(s.Directory, cfg.TopN, cfg.StableTies)
+		output.Types = NewPairListFromMap(s.Types, cfg.TopN, cfg.StableTies)
+		output.Severity = NewPairListFromMap(s.Severity, cfg.TopN, cfg.StableTies)
+		return output
+	}
+
+	// we have a filter. only run the asked one.
+	if cfg.Tags {
+		output.Tags = NewPairListFromMap(s.Tags, cfg.TopN, cfg.StableTies)
+	}
+	if cfg.Authors {
+		output.Authors = NewPairListFromMap(s.Authors, cfg.TopN, cfg.StableTies)
+	}
+	if cfg.Directory {
+		output.Directory = NewPairListFromMap(s.Directory, cfg.TopN, cfg.StableTies)
+	}
+	if cfg.Types {
+		output.Types = NewPairListFromMap(s.Types, cfg.TopN, cfg.StableTies)
+	}
+	if cfg.Severity {
+		output.Severity = NewPairListFromMap(s.Severity, cfg.TopN, cfg.StableTies)
+	}
+	if cfg.CveAuthors {
+		output.CveAuthors = NewPairListFromMap(s.CveAuthors, cfg.TopN, cfg.StableTies)
+	}
+	if cfg.ExtractorTypes {
+		output.ExtractorTypes = NewPairListFromMap(s.ExtractorTypes, cfg.TopN, cfg.StableTies)
+	}
+	if cfg.OWASP {
+		output.OWASP = NewPairListFromMap(s.OWASP, cfg.TopN, cfg.StableTies)
+	}
+	if cfg.QualityGrades {
+		output.GradeDistribution = NewPairListFromMap(ComputeGradeDistribution(s.Records, cfg.Verbose), cfg.TopN, cfg.StableTies)
+	}
+	if cfg.CVSSGrades {
+		output.CVSSGrades = NewPairListFromMap(ComputeCVSSGrades(s.Records), cfg.TopN, cfg.StableTies)
+	}
+	return output
+}
+
+// ExpandCompoundTags returns the tag counts with every component of the
+// compound tags split on separator counted as an additional tag.
+func ExpandCompoundTags(tagMap map[string]int, separator string) map[string]int {
+	expanded := make(map[string]int, len(tagMap))
+	for tag, value := range tagMap {
+		expanded[tag] += value
+		if !strings.Contains(tag, separator) {
+			continue
+		}
+		for _, component := range strings.Split(tag, separator) {
+			if component = strings.TrimSpace(component); component != "" {
+				expanded[component] += value
+			}
+		}
+	}
+	return expanded
+}
@@ -0,0 +1,223 @@
+package templatestats
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+// fixtureDirectory holds a small set of templates along with a helper file
+const fixtureDirectory = "testdata/templates"
+
+func TestComputeStats(t *testing.T) {
+	output, err := ComputeStats(StatsConfig{
+		TemplateDirectory: fixtureDirectory,
+		HelperPattern:     "helpers/*",
+		StableTies:        true,
+	})
+	if err != nil {
+		t.Fatalf("could not compute stats: %s", err)
+	}
+
+	expected := &Output{
+		Tags: PairList{
+			{"apache", 1}, {"config", 1}, {"cve", 1}, {"cve2023", 1}, {"dns", 1}, {"exposure", 1}, {"git", 1}, {"rce", 1},
+		},
+		Authors:     PairList{{"geeknik", 2}, {"pdteam", 2}},
+		Directory:   PairList{{"http", 2}, {"dns", 1}},
+		Severity:    PairList{{"critical", 1}, {"low", 1}, {"medium", 1}},
+		Types:       PairList{{"http", 2}, {"dns", 1}},
+		HelperCount: 1,
+	}
+	if !reflect.DeepEqual(output, expected) {
+		t.Fatalf("unexpected stats:\ngot:  %+v\nwant: %+v", output, expected)
+	}
+}
+
+func TestComputeStatsCategories(t *testing.T) {
+	output, err := ComputeStats(StatsConfig{
+		TemplateDirectory: fixtureDirectory,
+		HelperPattern:     "helpers/*",
+		StableTies:        true,
+		CveAuthors:        true,
+		ExtractorTypes:    true,
+		CVSSGrades:        true,
+	})
+	if err != nil {
+		t.Fatalf("could not compute stats: %s", err)
+	}
+
+	expected := &Output{
+		CveAuthors:     PairList{{"geeknik", 1}, {"pdteam", 1}},
+		ExtractorTypes: PairList{{"regex", 1}},
+		CVSSGrades:     PairList{{"D/F", 1}},
+		HelperCount:    1,
+
+		explicitCategories: true,
+	}
+	if !reflect.DeepEqual(output, expected) {
+		t.Fatalf("unexpected stats:\ngot:  %+v\nwant: %+v", output, expected)
+	}
+
+	var categories []string
+	for _, column := range output.Columns() {
+		categories = append(categories, column.Category)
+	}
+	if expected := []string{"cve_authors", "extractor_types", "cvss_grades"}; !reflect.DeepEqual(categories, expected) {
+		t.Fatalf("unexpected columns %v, want %v", categories, expected)
+	}
+}
+
+func TestComputeStatsFilters(t *testing.T) {
+	stats, err := Collect(StatsConfig{
+		TemplateDirectory: fixtureDirectory,
+		HelperPattern:     "helpers/*",
+		FilterSeverities:  []string{"critical", "medium"},
+		ExcludeTags:       []string{"git"},
+	})
+	if err != nil {
+		t.Fatalf("could not collect stats: %s", err)
+	}
+
+	var ids []string
+	for _, record := range stats.Records {
+		ids = append(ids, record.ID)
+	}
+	if expected := []string{"CVE-2023-1234", "git-config"}; !reflect.DeepEqual(ids, expected) {
+		t.Fatalf("unexpected templates %v, want %v", ids, expected)
+	}
+	if expected := []string{"dns/dns-rebind.yaml"}; !reflect.DeepEqual(stats.Skipped, expected) {
+		t.Fatalf("unexpected skipped templates %v, want %v", stats.Skipped, expected)
+	}
+	if _, ok := stats.Tags["git"]; ok {
+		t.Fatalf("excluded tag git was counted: %v", stats.Tags)
+	}
+}
+
+func TestCollectParseFailures(t *testing.T) {
+	directory := t.TempDir()
+	if err := os.WriteFile(filepath.Join(directory, "broken.yaml"), []byte("id: [broken"), 0644); err != nil {
+		t.Fatalf("could not write template: %s", err)
+	}
+
+	stats, err := Collect(StatsConfig{TemplateDirectory: directory, FilterSeverities: []string{"critical"}})
+	if err != nil {
+		t.Fatalf("could not collect stats: %s", err)
+	}
+	if expected := []string{"broken.yaml"}; !reflect.DeepEqual(stats.ParseFailures, expected) {
+		t.Fatalf("unexpected parse failures %v, want %v", stats.ParseFailures, expected)
+	}
+	if len(stats.Skipped) > 0 {
+		t.Fatalf("parse failures were reported as skipped: %v", stats.Skipped)
+	}
+}
+
+// syntheticTemplateCount is the number of templates generated for the
+// worker comparison
+const syntheticTemplateCount = 1000
+
+// writeSyntheticTemplates generates n templates spread over a few
+// directories, severities, authors and tags.
+func writeSyntheticTemplates(tb testing.TB, n int) string {
+	tb.Helper()
+
+	directory := tb.TempDir()
+	severities := []string{"info", "low", "medium", "high", "critical"}
+	for i := 0; i < n; i++ {
+		templateDirectory := filepath.Join(directory, []string{"http", "dns", "network"}[i%3], fmt.Sprintf("group-%d", i%10))
+		if err := os.MkdirAll(templateDirectory, 0755); err != nil {
+			tb.Fatalf("could not create template directory: %s", err)
+		}
+		template := fmt.Sprintf(`id: synthetic-%d
+
+info:
+  name: Synthetic Template %d
+  author: author%d,author%d
+  severity: %s
+  description: Synthetic template number %d.
+  reference:
+    - https://example.com/%d
+  classification:
+    cvss-score: %d.5
+  tags: synthetic,tag%d,tag%d
+
+requests:
+  - method: GET
+    path:
+      - "{{BaseURL}}/%d"
+    extractors:
+      - type: regex
+        regex:
+          - "value-%d"
+`, i, i, i%7, i%13, severities[i%len(severities)], i, i, i%10, i%5, i%11, i, i)
+		if err := os.WriteFile(filepath.Join(templateDirectory, fmt.Sprintf("synthetic-%d.yaml", i)), []byte(template), 0644); err != nil {
+			tb.Fatalf("could not write template: %s", err)
+		}
+	}
+	return directory
+}
+
+// syntheticStatsConfig requests every category of the synthetic templates
+func syntheticStatsConfig(directory string, workers int) StatsConfig {
+	return StatsConfig{
+		TemplateDirectory: directory,
+		Workers:           workers,
+		StableTies:        true,
+		Tags:              true,
+		Authors:           true,
+		Directory:         true,
+		Severity:          true,
+		Types:             true,
+		CveAuthors:        true,
+		ExtractorTypes:    true,
+		QualityGrades:     true,
+		OWASP:             true,
+		CVSSGrades:        true,
+	}
+}
+
+func TestComputeStatsWorkers(t *testing.T) {
+	directory := writeSyntheticTemplates(t, syntheticTemplateCount)
+	// use at least two workers so the pool is exercised on a single cpu
+	workers := runtime.NumCPU()
+	if workers < 2 {
+		workers = 2
+	}
+
+	sequential, err := ComputeStats(syntheticStatsConfig(directory, 1))
+	if err != nil {
+		t.Fatalf("could not compute stats with one worker: %s", err)
+	}
+
+	parallel, err := ComputeStats(syntheticStatsConfig(directory, workers))
+	if err != nil {
+		t.Fatalf("could not compute stats with %d workers: %s", workers, err)
+	}
+
+	if !reflect.DeepEqual(sequential, parallel) {
+		t.Fatalf("stats differ between 1 and %d workers:\n1:  %+v\n%d: %+v", workers, sequential, workers, parallel)
+	}
+	if severity := sequential.Severity; len(severity) != 5 || severity[0].Value != syntheticTemplateCount/5 {
+		t.Fatalf("unexpected severity counts %v", severity)
+	}
+}
+
+// BenchmarkComputeStats compares a single worker with one worker per CPU,
+// run with -bench ComputeStats to see the speedup of the worker pool.
+func BenchmarkComputeStats(b *testing.B) {
+	directory := writeSyntheticTemplates(b, syntheticTemplateCount)
+
+	for _, workers := range []int{1, runtime.NumCPU()} {
+		b.Run(fmt.Sprintf("workers-%d", workers), func(b *testing.B) {
+			cfg := syntheticStatsConfig(directory, workers)
+			for i := 0; i < b.N; i++ {
+				if _, err := ComputeStats(cfg); err != nil {
+					b.Fatalf("could not compute stats: %s", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package templatestats
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+	sliceutil "github.com/projectdiscovery/utils/slice"
+)
+
+// cvssRating is a band of the CVSS v3 qualitative rating scale
+type cvssRating struct {
+	MinScore float64
+	Severity string
+	Grade    string
+}
+
+// cvssRatings are the rating bands from the highest, a score of 0 is rated
+// as info. Both the severity and the letter grade of a score come from it.
+var cvssRatings = []cvssRating{
+	{MinScore: 9, Severity: "critical", Grade: "D/F"},
+	{MinScore: 7, Severity: "high", Grade: "C"},
+	{MinScore: 4, Severity: "medium", Grade: "B"},
+	{MinScore: 0.1, Severity: "low", Grade: "A"},
+	{MinScore: 0, Severity: "info", Grade: "A"},
+}
+
+// CVSSGradeSeverities maps every CVSS grade to the severities of its bands
+var CVSSGradeSeverities = cvssGradeSeverities()
+
+func cvssGradeSeverities() map[string][]string {
+	severities := make(map[string][]string)
+	for _, rating := range cvssRatings {
+		severities[rating.Grade] = append(severities[rating.Grade], rating.Severity)
+	}
+	return severities
+}
+
+// ratingOf returns the rating band of a CVSS score
+func ratingOf(score float64) cvssRating {
+	for _, rating := range cvssRatings {
+		if score >= rating.MinScore {
+			return rating
+		}
+	}
+	return cvssRatings[len(cvssRatings)-1]
+}
+
+// CVSSSeverity returns the severity of a CVSS v3 score
+func CVSSSeverity(score float64) string {
+	return ratingOf(score).Severity
+}
+
+// CVSSAgrees reports whether a severity label matches the rating of a CVSS score
+func CVSSAgrees(score float64, severity string) bool {
+	return CVSSSeverity(score) == severity
+}
+
+// CVSSGradeAgrees reports whether a severity label is one of the severities
+// of the grade of a CVSS score, grade A covers both info and low.
+func CVSSGradeAgrees(score float64, severity string) bool {
+	return sliceutil.Contains(CVSSGradeSeverities[CVSSGrade(score)], severity)
+}
+
+// TemplateCVSSScore returns the info.classification.cvss-score of a template
+func TemplateCVSSScore(record Record) (float64, bool) {
+	classification, ok := record.Info["classification"].(map[interface{}]interface{})
+	if !ok {
+		return 0, false
+	}
+	value, ok := classification["cvss-score"]
+	if !ok {
+		return 0, false
+	}
+	score, err := strconv.ParseFloat(strings.TrimSpace(types.ToString(value)), 64)
+	if err != nil {
+		return 0, false
+	}
+	return score, true
+}
+
+// CVSSGrade buckets a CVSS score into a letter grade
+func CVSSGrade(score float64) string {
+	return ratingOf(score).Grade
+}
+
+// ComputeCVSSGrades counts the templates of every CVSS grade
+func ComputeCVSSGrades(records []Record) map[string]int {
+	grades := make(map[string]int)
+	for _, record := range records {
+		if score, ok := TemplateCVSSScore(record); ok {
+			grades[CVSSGrade(score)]++
+		}
+	}
+	return grades
+}
@@ -0,0 +1,62 @@
+package templatestats
+
+import (
+	"testing"
+
+	sliceutil "github.com/projectdiscovery/utils/slice"
+)
+
+func TestCVSSRatings(t *testing.T) {
+	tests := []struct {
+		score    float64
+		severity string
+		grade    string
+	}{
+		{0, "info", "A"},
+		{0.1, "low", "A"},
+		{3.9, "low", "A"},
+		{4, "medium", "B"},
+		{6.9, "medium", "B"},
+		{7, "high", "C"},
+		{8.9, "high", "C"},
+		{9, "critical", "D/F"},
+		{10, "critical", "D/F"},
+	}
+	for _, test := range tests {
+		if severity := CVSSSeverity(test.score); severity != test.severity {
+			t.Errorf("CVSSSeverity(%.1f) = %s, want %s", test.score, severity, test.severity)
+		}
+		if grade := CVSSGrade(test.score); grade != test.grade {
+			t.Errorf("CVSSGrade(%.1f) = %s, want %s", test.score, grade, test.grade)
+		}
+		if !sliceutil.Contains(CVSSGradeSeverities[test.grade], test.severity) {
+			t.Errorf("grade %s does not list severity %s", test.grade, test.severity)
+		}
+	}
+	if CVSSAgrees(0, "low") {
+		t.Errorf("a score of 0 must not agree with a low severity")
+	}
+}
+
+func TestCVSSGradeAgrees(t *testing.T) {
+	tests := []struct {
+		score        float64
+		severity     string
+		gradeAgrees  bool
+		ratingAgrees bool
+	}{
+		{2.0, "info", true, false},
+		{0, "low", true, false},
+		{2.0, "low", true, true},
+		{5.0, "medium", true, true},
+		{9.1, "medium", false, false},
+	}
+	for _, test := range tests {
+		if agrees := CVSSGradeAgrees(test.score, test.severity); agrees != test.gradeAgrees {
+			t.Errorf("CVSSGradeAgrees(%.1f, %s) = %v, want %v", test.score, test.severity, agrees, test.gradeAgrees)
+		}
+		if agrees := CVSSAgrees(test.score, test.severity); agrees != test.ratingAgrees {
+			t.Errorf("CVSSAgrees(%.1f, %s) = %v, want %v", test.score, test.severity, agrees, test.ratingAgrees)
+		}
+	}
+}
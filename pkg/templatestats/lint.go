@@ -0,0 +1,38 @@
+package templatestats
+
+import "sort"
+
+// lint issue levels, from the most to the least severe
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+	LintInfo    = "info"
+)
+
+type LintIssue struct {
+	Path  string `json:"path"`
+	Field string `json:"field"`
+	Level string `json:"level"`
+}
+
+type DuplicateEntry struct {
+	ID    string   `json:"id" yaml:"id"`
+	Paths []string `json:"paths" yaml:"paths"`
+}
+
+// FindDuplicateIDs returns the template ids declared by more than one file
+func FindDuplicateIDs(records []Record) []DuplicateEntry {
+	paths := make(map[string][]string)
+	for _, record := range records {
+		paths[record.ID] = append(paths[record.ID], record.Path)
+	}
+	var duplicates []DuplicateEntry
+	for id, files := range paths {
+		if len(files) > 1 {
+			sort.Strings(files)
+			duplicates = append(duplicates, DuplicateEntry{ID: id, Paths: files})
+		}
+	}
+	sort.Slice(duplicates, func(i, j int) bool { return duplicates[i].ID < duplicates[j].ID })
+	return duplicates
+}
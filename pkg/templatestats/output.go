@@ -0,0 +1,130 @@
+package templatestats
+
+import "sort"
+
+type Pair struct {
+	Key   string `json:"name" yaml:"name"`
+	Value int    `json:"count" yaml:"count"`
+}
+
+type PairList []Pair
+
+func (p PairList) Len() int           { return len(p) }
+func (p PairList) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+func (p PairList) Less(i, j int) bool { return p[i].Value > p[j].Value }
+
+// StablePairList orders pairs with equal counts by their key so that the
+// order of ties does not depend on map iteration.
+type StablePairList PairList
+
+func (p StablePairList) Len() int      { return len(p) }
+func (p StablePairList) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p StablePairList) Less(i, j int) bool {
+	if p[i].Value != p[j].Value {
+		return p[i].Value > p[j].Value
+	}
+	return p[i].Key < p[j].Key
+}
+
+// NewPairListFromMap returns the n most common entries of data, n of 0
+// keeps all of them. stableTies orders equal counts by their key.
+func NewPairListFromMap(data map[string]int, n int, stableTies bool) PairList {
+	pairs := make(PairList, len(data))
+	i := 0
+
+	for k, v := range data {
+		pairs[i] = Pair{k, v}
+		i++
+	}
+	if stableTies {
+		sort.Sort(StablePairList(pairs))
+	} else {
+		sort.Sort(pairs)
+	}
+
+	final := make([]Pair, 0, len(pairs))
+	for i, data := range pairs {
+		if n != 0 && i == n {
+			break
+		}
+		final = append(final, data)
+	}
+	return final
+}
+
+type Output struct {
+	Tags              PairList `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Authors           PairList `json:"authors,omitempty" yaml:"authors,omitempty"`
+	Directory         PairList `json:"directory,omitempty" yaml:"directory,omitempty"`
+	Severity          PairList `json:"severity,omitempty" yaml:"severity,omitempty"`
+	Types             PairList `json:"types,omitempty" yaml:"types,omitempty"`
+	CveAuthors        PairList `json:"cve_authors,omitempty" yaml:"cve_authors,omitempty"`
+	ExtractorTypes    PairList `json:"extractor_types,omitempty" yaml:"extractor_types,omitempty"`
+	GradeDistribution PairList `json:"grade_distribution,omitempty" yaml:"grade_distribution,omitempty"`
+	OWASP             PairList `json:"owasp,omitempty" yaml:"owasp,omitempty"`
+	CVSSGrades        PairList `json:"cvss_grades,omitempty" yaml:"cvss_grades,omitempty"`
+	ExpandedTags      PairList `json:"expanded_tags,omitempty" yaml:"expanded_tags,omitempty"`
+	// Duplicates are the template ids declared by more than one file
+	Duplicates []DuplicateEntry `json:"duplicates,omitempty" yaml:"duplicates,omitempty"`
+	// HelperCount is the number of helper files excluded from the stats
+	HelperCount int `json:"helper_count,omitempty" yaml:"helper_count,omitempty"`
+
+	// explicitCategories is set when only some categories were requested,
+	// the empty default columns are then left out.
+	explicitCategories bool
+}
+
+// Column is a single name/count column pair of the markdown table
+type Column struct {
+	Category string
+	Header   string
+	Pairs    PairList
+}
+
+// Columns returns the markdown table columns. The default categories are
+// rendered unless categories were requested explicitly, the optional ones
+// only show up when populated.
+func (o *Output) Columns() []Column {
+	var columns []Column
+	for _, column := range []Column{
+		{Category: "tags", Header: "Tag", Pairs: o.Tags},
+		{Category: "authors", Header: "Author", Pairs: o.Authors},
+		{Category: "directory", Header: "Directory", Pairs: o.Directory},
+		{Category: "severity", Header: "Severity", Pairs: o.Severity},
+		{Category: "types", Header: "Type", Pairs: o.Types},
+	} {
+		if !o.explicitCategories || len(column.Pairs) > 0 {
+			columns = append(columns, column)
+		}
+	}
+	if len(o.CveAuthors) > 0 {
+		columns = append(columns, Column{Category: "cve_authors", Header: "CVE Author", Pairs: o.CveAuthors})
+	}
+	if len(o.ExtractorTypes) > 0 {
+		columns = append(columns, Column{Category: "extractor_types", Header: "Extractor Type", Pairs: o.ExtractorTypes})
+	}
+	if len(o.GradeDistribution) > 0 {
+		columns = append(columns, Column{Category: "grade_distribution", Header: "Grade", Pairs: o.GradeDistribution})
+	}
+	if len(o.OWASP) > 0 {
+		columns = append(columns, Column{Category: "owasp", Header: "OWASP Category", Pairs: o.OWASP})
+	}
+	if len(o.CVSSGrades) > 0 {
+		columns = append(columns, Column{Category: "cvss_grades", Header: "CVSS Grade", Pairs: o.CVSSGrades})
+	}
+	if len(o.ExpandedTags) > 0 {
+		columns = append(columns, Column{Category: "expanded_tags", Header: "Expanded Tag", Pairs: o.ExpandedTags})
+	}
+	return columns
+}
+
+// MaxItemCount returns the number of rows of the longest column
+func (o *Output) MaxItemCount() int {
+	max := 0
+	for _, column := range o.Columns() {
+		if newMax := len(column.Pairs); newMax > max {
+			max = newMax
+		}
+	}
+	return max
+}
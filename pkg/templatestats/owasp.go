@@ -0,0 +1,62 @@
+package templatestats
+
+import "strings"
+
+// owaspUnknown is the category of templates without any mapped tag
+const owaspUnknown = "Unknown"
+
+// owaspCategories maps nuclei tags to their OWASP Top 10 (2021) category
+var owaspCategories = map[string]string{
+	"idor":            "A01: Broken Access Control",
+	"lfi":             "A01: Broken Access Control",
+	"traversal":       "A01: Broken Access Control",
+	"redirect":        "A01: Broken Access Control",
+	"cors":            "A01: Broken Access Control",
+	"auth-bypass":     "A01: Broken Access Control",
+	"ssl":             "A02: Cryptographic Failures",
+	"tls":             "A02: Cryptographic Failures",
+	"crypto":          "A02: Cryptographic Failures",
+	"sqli":            "A03: Injection",
+	"xss":             "A03: Injection",
+	"ssti":            "A03: Injection",
+	"rce":             "A03: Injection",
+	"injection":       "A03: Injection",
+	"cmdi":            "A03: Injection",
+	"crlf":            "A03: Injection",
+	"ldap":            "A03: Injection",
+	"nosqli":          "A03: Injection",
+	"xxe":             "A05: Security Misconfiguration",
+	"misconfig":       "A05: Security Misconfiguration",
+	"exposure":        "A05: Security Misconfiguration",
+	"debug":           "A05: Security Misconfiguration",
+	"unauth":          "A05: Security Misconfiguration",
+	"eol":             "A06: Vulnerable and Outdated Components",
+	"outdated":        "A06: Vulnerable and Outdated Components",
+	"default-login":   "A07: Identification and Authentication Failures",
+	"weak-auth":       "A07: Identification and Authentication Failures",
+	"bruteforce":      "A07: Identification and Authentication Failures",
+	"deserialization": "A08: Software and Data Integrity Failures",
+	"ssrf":            "A10: Server-Side Request Forgery",
+}
+
+// TemplateOWASPCategories returns the distinct OWASP categories of a
+// template based on its tags.
+func TemplateOWASPCategories(tags []string) []string {
+	seen := make(map[string]struct{})
+	var categories []string
+	for _, tag := range tags {
+		category, ok := owaspCategories[strings.ToLower(strings.TrimSpace(tag))]
+		if !ok {
+			continue
+		}
+		if _, ok := seen[category]; ok {
+			continue
+		}
+		seen[category] = struct{}{}
+		categories = append(categories, category)
+	}
+	if len(categories) == 0 {
+		return []string{owaspUnknown}
+	}
+	return categories
+}
@@ -0,0 +1,53 @@
+package templatestats
+
+import (
+	"log"
+	"strings"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+)
+
+// QualityGrades are the grades given for 0, 1, 2, 3 and 4+ failed checks
+var QualityGrades = []string{"A", "B", "C", "D", "F"}
+
+// MinQualityTags is the number of tags expected from a grade A template
+const MinQualityTags = 3
+
+// TemplateQualityFailures returns the quality checks a template fails
+func TemplateQualityFailures(record Record) []string {
+	var failures []string
+	for _, field := range []string{"name", "author", "description", "reference"} {
+		if value, ok := record.Info[field]; !ok || value == nil || types.ToString(value) == "" {
+			failures = append(failures, field)
+		}
+	}
+	if !IsValidSeverity(strings.ToLower(types.ToString(record.Info["severity"]))) {
+		failures = append(failures, "severity")
+	}
+	if len(TemplateTags(record)) < MinQualityTags {
+		failures = append(failures, "tags")
+	}
+	return failures
+}
+
+// TemplateGrade returns the A-F quality grade of a template
+func TemplateGrade(record Record) string {
+	failures := len(TemplateQualityFailures(record))
+	if failures >= len(QualityGrades) {
+		failures = len(QualityGrades) - 1
+	}
+	return QualityGrades[failures]
+}
+
+// ComputeGradeDistribution counts the templates for every quality grade
+func ComputeGradeDistribution(records []Record, verbose bool) map[string]int {
+	gradeMap := make(map[string]int)
+	for _, record := range records {
+		grade := TemplateGrade(record)
+		gradeMap[grade]++
+		if grade == "F" && verbose {
+			log.Printf("[quality] grade F for template %s (%s)\n", record.Path, strings.Join(TemplateQualityFailures(record), ","))
+		}
+	}
+	return gradeMap
+}
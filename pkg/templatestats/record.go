@@ -0,0 +1,99 @@
+package templatestats
+
+import (
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+)
+
+// RequestTypeKeys are the top level keys declaring a nuclei request type
+var RequestTypeKeys = []string{"requests", "http", "dns", "network", "tcp", "file", "headless", "ssl", "websocket", "workflows"}
+
+// ValidSeverities are the severity values accepted by nuclei
+var ValidSeverities = []string{"info", "low", "medium", "high", "critical", "unknown"}
+
+func IsValidSeverity(severity string) bool {
+	for _, valid := range ValidSeverities {
+		if severity == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// Record is a parsed template which passed the id and info checks
+type Record struct {
+	Path    string
+	ID      string
+	Data    map[string]interface{}
+	Info    map[interface{}]interface{}
+	ModTime time.Time
+}
+
+// TemplateTags returns the non-empty tags of a template
+func TemplateTags(record Record) []string {
+	tags, ok := record.Info["tags"]
+	if !ok {
+		return nil
+	}
+	var result []string
+	for _, tag := range strings.Split(types.ToString(tags), ",") {
+		if tag != "" {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+func ExplodeCommaSeparatedField(field string) []string {
+	if !strings.Contains(field, ",") {
+		return []string{strings.ToLower(field)}
+	}
+
+	parts := strings.Split(field, ",")
+	partValues := make([]string, 0, len(parts))
+	for _, part := range parts {
+		partValues = append(partValues, strings.ToLower(strings.TrimSpace(part)))
+	}
+	return partValues
+}
+
+// RequestBlocks returns every request block declared by a template
+// across all of its request types.
+func RequestBlocks(data map[string]interface{}) []map[interface{}]interface{} {
+	var blocks []map[interface{}]interface{}
+	for _, key := range RequestTypeKeys {
+		items, ok := data[key].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, item := range items {
+			if block, ok := item.(map[interface{}]interface{}); ok {
+				blocks = append(blocks, block)
+			}
+		}
+	}
+	return blocks
+}
+
+// TemplateExtractorTypes returns the type of every extractor of a template
+func TemplateExtractorTypes(data map[string]interface{}) []string {
+	var extractorTypes []string
+	for _, block := range RequestBlocks(data) {
+		extractors, ok := block["extractors"].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, item := range extractors {
+			extractor, ok := item.(map[interface{}]interface{})
+			if !ok {
+				continue
+			}
+			if extractorType := strings.ToLower(types.ToString(extractor["type"])); extractorType != "" {
+				extractorTypes = append(extractorTypes, extractorType)
+			}
+		}
+	}
+	return extractorTypes
+}
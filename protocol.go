@@ -0,0 +1,95 @@
+package main
+
+import (
+	_ "embed"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+	sliceutil "github.com/projectdiscovery/utils/slice"
+	"gopkg.in/yaml.v2"
+)
+
+//go:embed protocol-expectations.yaml
+var protocolExpectationsData []byte
+
+// ProtocolSeverityExpectation lists the severities expected for a protocol
+type ProtocolSeverityExpectation struct {
+	Protocol   string   `yaml:"protocol"`
+	Severities []string `yaml:"severities"`
+}
+
+type ProtocolMismatch struct {
+	Path      string   `json:"path"`
+	Severity  string   `json:"severity"`
+	Protocols []string `json:"protocols"`
+}
+
+// loadProtocolExpectations reads the expectations from file, or the
+// embedded defaults when file is empty.
+func loadProtocolExpectations(file string) (map[string][]string, error) {
+	data := protocolExpectationsData
+	if file != "" {
+		var err error
+		if data, err = os.ReadFile(file); err != nil {
+			return nil, errors.Wrap(err, "could not read protocol expectations")
+		}
+	}
+	var items []ProtocolSeverityExpectation
+	if err := yaml.Unmarshal(data, &items); err != nil {
+		return nil, errors.Wrap(err, "could not parse protocol expectations")
+	}
+	expectations := make(map[string][]string, len(items))
+	for _, item := range items {
+		for _, severity := range item.Severities {
+			expectations[item.Protocol] = append(expectations[item.Protocol], strings.ToLower(severity))
+		}
+	}
+	return expectations, nil
+}
+
+// findProtocolMismatches returns the templates whose severity is not
+// expected for any of their protocols.
+func findProtocolMismatches(records []templateRecord, expectations map[string][]string) []ProtocolMismatch {
+	var mismatches []ProtocolMismatch
+	for _, record := range records {
+		severity := strings.ToLower(types.ToString(record.Info["severity"]))
+		var protocols []string
+		expected := false
+		for _, key := range templateRequestTypes(record) {
+			if key == "workflows" {
+				continue
+			}
+			protocol := templateTypeName(key)
+			protocols = append(protocols, protocol)
+			severities, ok := expectations[protocol]
+			if !ok || sliceutil.Contains(severities, severity) {
+				expected = true
+			}
+		}
+		if len(protocols) == 0 || expected {
+			continue
+		}
+		mismatches = append(mismatches, ProtocolMismatch{Path: record.Path, Severity: severity, Protocols: protocols})
+	}
+	sort.Slice(mismatches, func(i, j int) bool { return mismatches[i].Path < mismatches[j].Path })
+	return mismatches
+}
+
+func printProtocolMismatches(records []templateRecord, file string, writer io.Writer) error {
+	expectations, err := loadProtocolExpectations(file)
+	if err != nil {
+		return err
+	}
+	mismatches := findProtocolMismatches(records, expectations)
+
+	rows := make([][]string, 0, len(mismatches))
+	for _, mismatch := range mismatches {
+		rows = append(rows, []string{mismatch.Path, mismatch.Severity, strings.Join(mismatch.Protocols, ", ")})
+	}
+	writeReport(writer, mismatches, []string{"Path", "Severity", "Protocols"}, rows)
+	return nil
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+	"github.com/projectdiscovery/templates-stats/pkg/templatestats"
+)
+
+// shortDescriptionLength is the length under which a description is reported
+const shortDescriptionLength = 20
+
+type DescriptionStats struct {
+	Count  int                `json:"count"`
+	Mean   float64            `json:"mean"`
+	Median float64            `json:"median"`
+	P10    float64            `json:"p10"`
+	P90    float64            `json:"p90"`
+	Short  []ShortDescription `json:"short,omitempty"`
+}
+
+type ShortDescription struct {
+	Path        string `json:"path"`
+	Description string `json:"description"`
+	Length      int    `json:"length"`
+}
+
+func computeDescriptionStats(records []templateRecord) *DescriptionStats {
+	stats := &DescriptionStats{}
+	var lengths []float64
+	for _, record := range records {
+		description, ok := record.Info["description"]
+		if !ok || description == nil {
+			continue
+		}
+		descriptionStr := strings.TrimSpace(types.ToString(description))
+		length := utf8.RuneCountInString(descriptionStr)
+		lengths = append(lengths, float64(length))
+		if length < shortDescriptionLength {
+			stats.Short = append(stats.Short, ShortDescription{Path: record.Path, Description: descriptionStr, Length: length})
+		}
+	}
+	stats.Count = len(lengths)
+	stats.Mean = mean(lengths)
+	stats.Median = percentile(lengths, 50)
+	stats.P10 = percentile(lengths, 10)
+	stats.P90 = percentile(lengths, 90)
+	sort.Slice(stats.Short, func(i, j int) bool { return stats.Short[i].Length < stats.Short[j].Length })
+	return stats
+}
+
+func printDescriptionStats(records []templateRecord, writer io.Writer) {
+	stats := computeDescriptionStats(records)
+	if *jsonOutput {
+		if err := json.NewEncoder(writer).Encode(stats); err != nil {
+			log.Fatalf("Could not encode json: %s\n", err)
+		}
+		return
+	}
+	renderTable(writer, []string{"Descriptions", "Mean", "Median", "P10", "P90"}, [][]string{{
+		strconv.Itoa(stats.Count),
+		fmt.Sprintf("%.1f", stats.Mean),
+		fmt.Sprintf("%.1f", stats.Median),
+		fmt.Sprintf("%.1f", stats.P10),
+		fmt.Sprintf("%.1f", stats.P90),
+	}})
+
+	rows := make([][]string, 0, len(stats.Short))
+	for _, short := range stats.Short {
+		rows = append(rows, []string{short.Path, short.Description, strconv.Itoa(short.Length)})
+	}
+	fmt.Fprintln(writer)
+	renderTable(writer, []string{"Path", "Short Description", "Length"}, rows)
+}
+
+// maxHealthScore is the health score of a template passing every quality check
+const maxHealthScore = 5
+
+// templateHealthScore returns maxHealthScore minus the failed quality checks
+func templateHealthScore(record templateRecord) int {
+	score := maxHealthScore - len(templatestats.TemplateQualityFailures(record))
+	if score < 0 {
+		return 0
+	}
+	return score
+}
+
+type DirectoryHealth struct {
+	Directory             string  `json:"directory"`
+	MeanHealth            float64 `json:"mean_health"`
+	PctGradeA             float64 `json:"pct_grade_A"`
+	PctMissingDescription float64 `json:"pct_missing_description"`
+	PctMissingReference   float64 `json:"pct_missing_reference"`
+}
+
+// computeDirectoryHealth aggregates the template health scores per top level
+// directory, the least healthy directory first.
+func computeDirectoryHealth(records []templateRecord, directory string) []DirectoryHealth {
+	type healthBucket struct {
+		scores                                       []float64
+		gradeA, missingDescription, missingReference int
+	}
+	buckets := make(map[string]*healthBucket)
+	for _, record := range records {
+		dir := topLevelDirectory(directory, record.Path)
+		bucket, ok := buckets[dir]
+		if !ok {
+			bucket = &healthBucket{}
+			buckets[dir] = bucket
+		}
+		failures := templatestats.TemplateQualityFailures(record)
+		bucket.scores = append(bucket.scores, float64(templateHealthScore(record)))
+		if len(failures) == 0 {
+			bucket.gradeA++
+		}
+		for _, failure := range failures {
+			switch failure {
+			case "description":
+				bucket.missingDescription++
+			case "reference":
+				bucket.missingReference++
+			}
+		}
+	}
+
+	health := make([]DirectoryHealth, 0, len(buckets))
+	for dir, bucket := range buckets {
+		total := float64(len(bucket.scores))
+		health = append(health, DirectoryHealth{
+			Directory:             dir,
+			MeanHealth:            mean(bucket.scores),
+			PctGradeA:             float64(bucket.gradeA) * 100 / total,
+			PctMissingDescription: float64(bucket.missingDescription) * 100 / total,
+			PctMissingReference:   float64(bucket.missingReference) * 100 / total,
+		})
+	}
+	sort.Slice(health, func(i, j int) bool {
+		if health[i].MeanHealth != health[j].MeanHealth {
+			return health[i].MeanHealth < health[j].MeanHealth
+		}
+		return health[i].Directory < health[j].Directory
+	})
+	return health
+}
+
+func printDirectoryHealth(records []templateRecord, directory string, writer io.Writer) {
+	health := computeDirectoryHealth(records, directory)
+
+	rows := make([][]string, 0, len(health))
+	for _, item := range health {
+		rows = append(rows, []string{
+			item.Directory,
+			fmt.Sprintf("%.2f", item.MeanHealth),
+			fmt.Sprintf("%.1f%%", item.PctGradeA),
+			fmt.Sprintf("%.1f%%", item.PctMissingDescription),
+			fmt.Sprintf("%.1f%%", item.PctMissingReference),
+		})
+	}
+	writeReport(writer, health, []string{"Directory", "Mean Health", "Grade A", "Missing Description", "Missing Reference"}, rows)
+}
+
+// anomalyThreshold is the number of standard deviations from the mean after
+// which a template metric is considered anomalous
+const anomalyThreshold = 2
+
+type AnomalousTemplate struct {
+	Path      string   `json:"path"`
+	Anomalies []string `json:"anomalies"`
+}
+
+// templateMetric is a numeric property of a template compared across the corpus
+type templateMetric struct {
+	name  string
+	value func(record templateRecord) float64
+}
+
+var anomalyMetrics = []templateMetric{
+	{name: "tag count", value: func(record templateRecord) float64 { return float64(len(templatestats.TemplateTags(record))) }},
+	{name: "description length", value: func(record templateRecord) float64 {
+		return float64(utf8.RuneCountInString(strings.TrimSpace(types.ToString(record.Info["description"]))))
+	}},
+	{name: "reference count", value: func(record templateRecord) float64 { return float64(len(templateReferences(record))) }},
+}
+
+// findAnomalousTemplates returns the templates with a metric further than
+// anomalyThreshold standard deviations from the corpus mean.
+func findAnomalousTemplates(records []templateRecord) []AnomalousTemplate {
+	anomalies := make([][]string, len(records))
+	for _, metric := range anomalyMetrics {
+		values := make([]float64, len(records))
+		for i, record := range records {
+			values[i] = metric.value(record)
+		}
+		m, sd := mean(values), stddev(values)
+		if sd == 0 {
+			continue
+		}
+		for i, value := range values {
+			if deviation := (value - m) / sd; deviation > anomalyThreshold || deviation < -anomalyThreshold {
+				anomalies[i] = append(anomalies[i], fmt.Sprintf("%s %g (%.1f sd from mean %.1f)", metric.name, value, deviation, m))
+			}
+		}
+	}
+
+	var result []AnomalousTemplate
+	for i, record := range records {
+		if len(anomalies[i]) > 0 {
+			result = append(result, AnomalousTemplate{Path: record.Path, Anomalies: anomalies[i]})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Path < result[j].Path })
+	return result
+}
+
+func printAnomalousTemplates(records []templateRecord, writer io.Writer) {
+	anomalies := findAnomalousTemplates(records)
+
+	rows := make([][]string, 0, len(anomalies))
+	for _, anomaly := range anomalies {
+		rows = append(rows, []string{anomaly.Path, strings.Join(anomaly.Anomalies, "; ")})
+	}
+	writeReport(writer, anomalies, []string{"Path", "Anomalies"}, rows)
+}
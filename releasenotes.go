@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+	"github.com/projectdiscovery/templates-stats/pkg/templatestats"
+)
+
+// releaseNotesTop is the number of authors and tags listed in release notes
+const releaseNotesTop = 5
+
+// renderReleaseNotes writes a markdown release note section for the added templates
+func renderReleaseNotes(records []templateRecord, writer io.Writer) {
+	authorMap := make(map[string]int)
+	tagMap := make(map[string]int)
+	cveYearMap := make(map[string]int)
+	bySeverity := make(map[string][]templateRecord)
+	for _, record := range records {
+		for _, author := range templateAuthors(record) {
+			authorMap[author]++
+		}
+		for _, tag := range templatestats.TemplateTags(record) {
+			tagMap[tag]++
+		}
+		if parts := strings.Split(record.ID, "-"); isCveTemplate(record) && len(parts) > 2 {
+			cveYearMap[parts[1]]++
+		}
+		severity := strings.ToLower(types.ToString(record.Info["severity"]))
+		bySeverity[severity] = append(bySeverity[severity], record)
+	}
+
+	fmt.Fprintf(writer, "## New Templates\n\n")
+	fmt.Fprintf(writer, "**Total new templates:** %d\n\n", len(records))
+
+	fmt.Fprintf(writer, "### Top %d New Authors\n\n", releaseNotesTop)
+	renderTable(writer, []string{"Author", "Count"}, pairRows(newPairListFromMap(authorMap, releaseNotesTop)))
+
+	years := make([]string, 0, len(cveYearMap))
+	for year := range cveYearMap {
+		years = append(years, year)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(years)))
+	yearRows := make([][]string, 0, len(years))
+	for _, year := range years {
+		yearRows = append(yearRows, []string{year, strconv.Itoa(cveYearMap[year])})
+	}
+	fmt.Fprintf(writer, "\n### New CVEs by Year\n\n")
+	renderTable(writer, []string{"Year", "Count"}, yearRows)
+
+	fmt.Fprintf(writer, "\n### Top %d New Tags\n\n", releaseNotesTop)
+	renderTable(writer, []string{"Tag", "Count"}, pairRows(newPairListFromMap(tagMap, releaseNotesTop)))
+
+	severities := make([]string, 0, len(bySeverity))
+	for severity := range bySeverity {
+		severities = append(severities, severity)
+	}
+	sort.Slice(severities, func(i, j int) bool { return severityRank(severities[i]) > severityRank(severities[j]) })
+	fmt.Fprintf(writer, "\n### New Templates by Severity\n")
+	for _, severity := range severities {
+		templates := bySeverity[severity]
+		sort.Slice(templates, func(i, j int) bool { return templates[i].ID < templates[j].ID })
+
+		title := severity
+		if title == "" {
+			title = "unknown"
+		}
+		fmt.Fprintf(writer, "\n#### %s (%d)\n\n", strings.ToUpper(title[:1])+title[1:], len(templates))
+		for _, template := range templates {
+			fmt.Fprintf(writer, "- [%s] %s (%s)\n", template.ID, types.ToString(template.Info["name"]), explodeAuthorsAndJoin(types.ToString(template.Info["author"])))
+		}
+	}
+}
+
+// pairRows converts a pair list into name/count table rows
+func pairRows(pairs PairList) [][]string {
+	rows := make([][]string, 0, len(pairs))
+	for _, pair := range pairs {
+		rows = append(rows, []string{pair.Key, strconv.Itoa(pair.Value)})
+	}
+	return rows
+}
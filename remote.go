@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var remoteClient = &http.Client{Timeout: 30 * time.Second}
+
+type CrossRepoConflict struct {
+	ID   string `json:"id"`
+	Path string `json:"path"`
+}
+
+// fetchRemoteIDs downloads a plain text list of template ids, one per line
+func fetchRemoteIDs(url string) (map[string]struct{}, error) {
+	resp, err := remoteClient.Get(url)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch remote ids")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected remote ids status code %d", resp.StatusCode)
+	}
+	ids := make(map[string]struct{})
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		id := strings.TrimSpace(scanner.Text())
+		if id == "" || strings.HasPrefix(id, "#") {
+			continue
+		}
+		ids[id] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "could not read remote ids")
+	}
+	return ids, nil
+}
+
+func findCrossRepoConflicts(records []templateRecord, remoteIDs map[string]struct{}) []CrossRepoConflict {
+	var conflicts []CrossRepoConflict
+	for _, record := range records {
+		if _, ok := remoteIDs[record.ID]; ok {
+			conflicts = append(conflicts, CrossRepoConflict{ID: record.ID, Path: record.Path})
+		}
+	}
+	sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].ID < conflicts[j].ID })
+	return conflicts
+}
+
+func printCrossRepoConflicts(records []templateRecord, url string, writer io.Writer) error {
+	remoteIDs, err := fetchRemoteIDs(url)
+	if err != nil {
+		return err
+	}
+	conflicts := findCrossRepoConflicts(records, remoteIDs)
+
+	rows := make([][]string, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		rows = append(rows, []string{conflict.ID, conflict.Path})
+	}
+	writeReport(writer, conflicts, []string{"ID", "Path"}, rows)
+	return nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+)
+
+type InventoryTemplate struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Author   string `json:"author"`
+	Version  string `json:"version,omitempty"`
+	Severity string `json:"severity"`
+	Type     string `json:"type"`
+	CVEID    string `json:"cve_id,omitempty"`
+}
+
+type TemplateInventory struct {
+	Templates []InventoryTemplate `json:"templates"`
+}
+
+// templateVersion returns the info.version or info.metadata.version of a template
+func templateVersion(record templateRecord) string {
+	if version := types.ToString(record.Info["version"]); version != "" {
+		return version
+	}
+	if metadata, ok := record.Info["metadata"].(map[interface{}]interface{}); ok {
+		return types.ToString(metadata["version"])
+	}
+	return ""
+}
+
+// templateCVEID returns the info.classification.cve-id of a template, or
+// the template id of CVE templates without a classification.
+func templateCVEID(record templateRecord) string {
+	if classification, ok := record.Info["classification"].(map[interface{}]interface{}); ok {
+		if cveID := strings.ToUpper(strings.TrimSpace(types.ToString(classification["cve-id"]))); cveID != "" {
+			return cveID
+		}
+	}
+	if isCveTemplate(record) {
+		return record.ID
+	}
+	return ""
+}
+
+func buildTemplateInventory(records []templateRecord) *TemplateInventory {
+	inventory := &TemplateInventory{Templates: make([]InventoryTemplate, 0, len(records))}
+	for _, record := range records {
+		var requestTypes []string
+		for _, key := range templateRequestTypes(record) {
+			requestTypes = append(requestTypes, templateTypeName(key))
+		}
+		inventory.Templates = append(inventory.Templates, InventoryTemplate{
+			ID:       record.ID,
+			Name:     types.ToString(record.Info["name"]),
+			Author:   strings.Join(templateAuthors(record), ","),
+			Version:  templateVersion(record),
+			Severity: strings.ToLower(types.ToString(record.Info["severity"])),
+			Type:     strings.Join(requestTypes, ","),
+			CVEID:    templateCVEID(record),
+		})
+	}
+	sort.Slice(inventory.Templates, func(i, j int) bool { return inventory.Templates[i].ID < inventory.Templates[j].ID })
+	return inventory
+}
+
+// writeTemplateInventory writes the sbom style inventory of every template
+func writeTemplateInventory(records []templateRecord, writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(buildTemplateInventory(records))
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+//go:embed template-schema.json
+var templateSchemaData []byte
+
+// templateSchemaURL is the resource name of the bundled template schema
+const templateSchemaURL = "template-schema.json"
+
+type SchemaViolation struct {
+	Path   string   `json:"path"`
+	Errors []string `json:"errors"`
+}
+
+// compileTemplateSchema compiles the json schema at file, or the bundled
+// schema when file is empty.
+func compileTemplateSchema(file string) (*jsonschema.Schema, error) {
+	data := templateSchemaData
+	if file != "" {
+		var err error
+		if data, err = os.ReadFile(file); err != nil {
+			return nil, errors.Wrap(err, "could not read schema")
+		}
+	}
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(templateSchemaURL, bytes.NewReader(data)); err != nil {
+		return nil, errors.Wrap(err, "could not load schema")
+	}
+	schema, err := compiler.Compile(templateSchemaURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compile schema")
+	}
+	return schema, nil
+}
+
+// toJSONValue converts a decoded yaml value into the types produced by
+// encoding/json so it can be validated against a json schema.
+func toJSONValue(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(stringKeys(value))
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var converted interface{}
+	if err := decoder.Decode(&converted); err != nil {
+		return nil, err
+	}
+	return converted, nil
+}
+
+// stringKeys recursively converts yaml maps into string keyed maps
+func stringKeys(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[fmt.Sprint(key)] = stringKeys(item)
+		}
+		return converted
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[key] = stringKeys(item)
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(v))
+		for i, item := range v {
+			converted[i] = stringKeys(item)
+		}
+		return converted
+	}
+	return value
+}
+
+// validationMessages flattens a validation error into its leaf messages
+func validationMessages(err *jsonschema.ValidationError) []string {
+	if len(err.Causes) == 0 {
+		location := err.InstanceLocation
+		if location == "" {
+			location = "/"
+		}
+		return []string{location + ": " + err.Message}
+	}
+	var messages []string
+	for _, cause := range err.Causes {
+		messages = append(messages, validationMessages(cause)...)
+	}
+	return messages
+}
+
+func findSchemaViolations(records []templateRecord, schema *jsonschema.Schema) []SchemaViolation {
+	var violations []SchemaViolation
+	for _, record := range records {
+		value, err := toJSONValue(record.Data)
+		if err != nil {
+			violations = append(violations, SchemaViolation{Path: record.Path, Errors: []string{err.Error()}})
+			continue
+		}
+		err = schema.Validate(value)
+		if err == nil {
+			continue
+		}
+		var messages []string
+		if validationErr, ok := err.(*jsonschema.ValidationError); ok {
+			messages = validationMessages(validationErr)
+		} else {
+			messages = []string{err.Error()}
+		}
+		violations = append(violations, SchemaViolation{Path: record.Path, Errors: messages})
+	}
+	sort.Slice(violations, func(i, j int) bool { return violations[i].Path < violations[j].Path })
+	return violations
+}
+
+func printSchemaViolations(records []templateRecord, file string, writer io.Writer) error {
+	schema, err := compileTemplateSchema(file)
+	if err != nil {
+		return err
+	}
+	violations := findSchemaViolations(records, schema)
+
+	rows := make([][]string, 0, len(violations))
+	for _, violation := range violations {
+		rows = append(rows, []string{violation.Path, strings.Join(violation.Errors, "\n")})
+	}
+	writeReport(writer, violations, []string{"Path", "Errors"}, rows)
+	return nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/templates-stats/pkg/templatestats"
+)
+
+// statsRefreshInterval is the interval the served stats are recomputed at
+const statsRefreshInterval = 5 * time.Minute
+
+// statsServer serves the stats of a template directory as a json api
+type statsServer struct {
+	cfg StatsConfig
+
+	mutex     sync.RWMutex
+	output    *Output
+	templates map[string]TemplateInfo
+	updatedAt time.Time
+}
+
+// refresh recomputes the stats of the template directory
+func (s *statsServer) refresh() error {
+	stats, err := templatestats.Collect(s.cfg)
+	if err != nil {
+		return err
+	}
+	templates := make(map[string]TemplateInfo, len(stats.Records))
+	for _, record := range stats.Records {
+		templates[record.ID] = newTemplateInfo(record)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.output = stats.Output(s.cfg)
+	s.templates = templates
+	s.updatedAt = time.Now()
+	return nil
+}
+
+func (s *statsServer) refreshPeriodically(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := s.refresh(); err != nil {
+			log.Printf("Could not refresh stats: %s\n", err)
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		log.Printf("Could not encode response: %s\n", err)
+	}
+}
+
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+func (s *statsServer) handleStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/stats"), "/")
+	switch {
+	case path == "":
+		writeJSON(w, http.StatusOK, s.output)
+	case strings.HasPrefix(path, "template/"):
+		id := strings.TrimPrefix(path, "template/")
+		template, ok := s.templates[id]
+		if !ok {
+			writeJSONError(w, http.StatusNotFound, fmt.Sprintf("no template found with id %s", id))
+			return
+		}
+		writeJSON(w, http.StatusOK, template)
+	default:
+		for _, column := range s.output.Columns() {
+			if column.Category == path {
+				writeJSON(w, http.StatusOK, column.Pairs)
+				return
+			}
+		}
+		writeJSONError(w, http.StatusNotFound, fmt.Sprintf("unknown category %s", path))
+	}
+}
+
+func (s *statsServer) handleRefresh(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if err := s.refresh(); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	writeJSON(w, http.StatusOK, map[string]string{"updated_at": s.updatedAt.Format(time.RFC3339)})
+}
+
+// serveStats computes the stats and serves them on port until the server fails
+func serveStats(cfg StatsConfig, port int) error {
+	server := &statsServer{cfg: cfg}
+	if err := server.refresh(); err != nil {
+		return err
+	}
+	go server.refreshPeriodically(statsRefreshInterval)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/stats", server.handleStats)
+	mux.HandleFunc("/stats/", server.handleStats)
+	mux.HandleFunc("/refresh", server.handleRefresh)
+
+	httpServer := &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	log.Printf("Serving stats on %s\n", httpServer.Addr)
+	return httpServer.ListenAndServe()
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// simulatedTopN are the -top values evaluated by -simulate-top
+var simulatedTopN = []int{1, 5, 10, 20, 50, 100}
+
+// statCategory is a named category of counted template metadata
+type statCategory struct {
+	Name   string
+	Counts map[string]int
+}
+
+type CoverageRow struct {
+	TopN     int            `json:"top_n"`
+	Coverage map[string]int `json:"coverage"`
+}
+
+type CoverageTable []CoverageRow
+
+// computeCoverageTable returns the total count covered by the top N
+// entries of every category for each of the simulated N values.
+func computeCoverageTable(categories []statCategory) CoverageTable {
+	table := make(CoverageTable, 0, len(simulatedTopN))
+	for _, n := range simulatedTopN {
+		row := CoverageRow{TopN: n, Coverage: make(map[string]int, len(categories))}
+		for _, category := range categories {
+			total := 0
+			for _, pair := range newPairListFromMap(category.Counts, n) {
+				total += pair.Value
+			}
+			row.Coverage[category.Name] = total
+		}
+		table = append(table, row)
+	}
+	return table
+}
+
+func printCoverageTable(categories []statCategory, writer io.Writer) {
+	table := computeCoverageTable(categories)
+
+	totals := make(map[string]int, len(categories))
+	header := []string{"Top N"}
+	for _, category := range categories {
+		header = append(header, category.Name)
+		for _, value := range category.Counts {
+			totals[category.Name] += value
+		}
+	}
+	rows := make([][]string, 0, len(table))
+	for _, coverage := range table {
+		row := []string{strconv.Itoa(coverage.TopN)}
+		for _, category := range categories {
+			covered := coverage.Coverage[category.Name]
+			pct := 0.0
+			if totals[category.Name] > 0 {
+				pct = float64(covered) * 100 / float64(totals[category.Name])
+			}
+			row = append(row, fmt.Sprintf("%d (%.1f%%)", covered, pct))
+		}
+		rows = append(rows, row)
+	}
+	writeReport(writer, table, header, rows)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// mean returns the arithmetic mean of values
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, value := range values {
+		total += value
+	}
+	return total / float64(len(values))
+}
+
+// percentile returns the p-th (0-100) percentile of values using linear
+// interpolation between the closest ranks.
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower == upper {
+		return sorted[lower]
+	}
+	return sorted[lower] + (rank-float64(lower))*(sorted[upper]-sorted[lower])
+}
+
+// fractionalRanks returns the 1-based rank of every value in descending
+// order, tied values share the average of their ranks.
+func fractionalRanks(values []float64) []float64 {
+	indexes := make([]int, len(values))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool { return values[indexes[i]] > values[indexes[j]] })
+
+	ranks := make([]float64, len(values))
+	for start := 0; start < len(indexes); {
+		end := start
+		for end+1 < len(indexes) && values[indexes[end+1]] == values[indexes[start]] {
+			end++
+		}
+		rank := float64(start+end)/2 + 1
+		for i := start; i <= end; i++ {
+			ranks[indexes[i]] = rank
+		}
+		start = end + 1
+	}
+	return ranks
+}
+
+// pearson returns the pearson correlation coefficient of x and y
+func pearson(x, y []float64) float64 {
+	meanX, meanY := mean(x), mean(y)
+	var covariance, varianceX, varianceY float64
+	for i := range x {
+		dx, dy := x[i]-meanX, y[i]-meanY
+		covariance += dx * dy
+		varianceX += dx * dx
+		varianceY += dy * dy
+	}
+	if varianceX == 0 || varianceY == 0 {
+		return 0
+	}
+	return covariance / math.Sqrt(varianceX*varianceY)
+}
+
+// spearmanPValue approximates the two-sided p-value of a spearman
+// correlation rho over n samples using the normal distribution.
+func spearmanPValue(rho float64, n int) float64 {
+	if n < 3 {
+		return 1
+	}
+	z := math.Abs(rho) * math.Sqrt(float64(n-1))
+	return math.Erfc(z / math.Sqrt2)
+}
+
+// stddev returns the population standard deviation of values
+func stddev(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	m := mean(values)
+	var variance float64
+	for _, value := range values {
+		variance += (value - m) * (value - m)
+	}
+	return math.Sqrt(variance / float64(len(values)))
+}
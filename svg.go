@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// svgPalette are the line colors of the chart series
+var svgPalette = []string{"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd", "#8c564b", "#e377c2", "#7f7f7f", "#bcbd22", "#17becf"}
+
+const (
+	svgWidth       = 960
+	svgHeight      = 480
+	svgMarginLeft  = 50
+	svgMarginRight = 180
+	svgMarginY     = 40
+	svgYTicks      = 5
+	svgMaxXLabels  = 12
+)
+
+// TimeSeries is a named series of values per YYYY-MM month
+type TimeSeries struct {
+	Name   string
+	Points map[string]int
+}
+
+// authorMonthlyTemplates returns the number of templates added by author per YYYY-MM month
+func authorMonthlyTemplates(directory, author string) (map[string]int, error) {
+	output, err := runGit(directory, "log", "--regexp-ignore-case", "--author="+regexp.QuoteMeta(author), "--diff-filter=A", "--name-only", "--format=>%ad", "--date=format:%Y-%m")
+	if err != nil {
+		return nil, err
+	}
+	months := make(map[string]int)
+	var month string
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, ">"):
+			month = strings.TrimPrefix(line, ">")
+		case strings.HasSuffix(line, ".yaml"):
+			months[month]++
+		}
+	}
+	return months, nil
+}
+
+// renderSVGLineChart writes a standalone svg chart with one line per series
+func renderSVGLineChart(series []TimeSeries, writer io.Writer) {
+	points := make(map[string]map[string]int, len(series))
+	maxValue := 1
+	for _, item := range series {
+		points[item.Name] = item.Points
+		for _, value := range item.Points {
+			if value > maxValue {
+				maxValue = value
+			}
+		}
+	}
+	months := monthRange(points)
+
+	plotWidth := float64(svgWidth - svgMarginLeft - svgMarginRight)
+	plotHeight := float64(svgHeight - 2*svgMarginY)
+	x := func(i int) float64 {
+		if len(months) < 2 {
+			return svgMarginLeft + plotWidth/2
+		}
+		return svgMarginLeft + float64(i)*plotWidth/float64(len(months)-1)
+	}
+	y := func(value int) float64 {
+		return svgMarginY + plotHeight - float64(value)*plotHeight/float64(maxValue)
+	}
+
+	fmt.Fprintf(writer, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" font-family=\"sans-serif\" font-size=\"11\">\n", svgWidth, svgHeight)
+	fmt.Fprintf(writer, "<rect width=\"100%%\" height=\"100%%\" fill=\"white\"/>\n")
+	fmt.Fprintf(writer, "<text x=\"%d\" y=\"20\" font-size=\"14\">Monthly templates added per author</text>\n", svgMarginLeft)
+
+	// axes and y ticks
+	fmt.Fprintf(writer, "<line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%.1f\" stroke=\"black\"/>\n", svgMarginLeft, svgMarginY, svgMarginLeft, y(0))
+	fmt.Fprintf(writer, "<line x1=\"%d\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\" stroke=\"black\"/>\n", svgMarginLeft, y(0), svgMarginLeft+plotWidth, y(0))
+	ticks := svgYTicks
+	if maxValue < ticks {
+		ticks = maxValue
+	}
+	for i := 0; i <= ticks; i++ {
+		value := maxValue * i / ticks
+		fmt.Fprintf(writer, "<text x=\"%d\" y=\"%.1f\" text-anchor=\"end\">%d</text>\n", svgMarginLeft-5, y(value)+4, value)
+		fmt.Fprintf(writer, "<line x1=\"%d\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\" stroke=\"#eee\"/>\n", svgMarginLeft, y(value), svgMarginLeft+plotWidth, y(value))
+	}
+
+	// x labels, skipping months when there are too many to fit
+	step := (len(months) + svgMaxXLabels - 1) / svgMaxXLabels
+	if step < 1 {
+		step = 1
+	}
+	for i := 0; i < len(months); i += step {
+		fmt.Fprintf(writer, "<text x=\"%.1f\" y=\"%.1f\" text-anchor=\"middle\">%s</text>\n", x(i), y(0)+15, months[i])
+	}
+
+	for i, item := range series {
+		color := svgPalette[i%len(svgPalette)]
+		coordinates := make([]string, 0, len(months))
+		for j, month := range months {
+			coordinates = append(coordinates, fmt.Sprintf("%.1f,%.1f", x(j), y(item.Points[month])))
+		}
+		fmt.Fprintf(writer, "<polyline fill=\"none\" stroke=\"%s\" stroke-width=\"2\" points=\"%s\"/>\n", color, strings.Join(coordinates, " "))
+
+		legendY := svgMarginY + i*18
+		fmt.Fprintf(writer, "<rect x=\"%.1f\" y=\"%d\" width=\"12\" height=\"12\" fill=\"%s\"/>\n", svgMarginLeft+plotWidth+15, legendY, color)
+		fmt.Fprintf(writer, "<text x=\"%.1f\" y=\"%d\">%s</text>\n", svgMarginLeft+plotWidth+32, legendY+10, html.EscapeString(item.Name))
+	}
+	fmt.Fprintln(writer, "</svg>")
+}
+
+func printAuthorStatsGraph(directory string, authorMap map[string]int, writer io.Writer) error {
+	var series []TimeSeries
+	for _, pair := range newPairListFromMap(authorMap, commitHistoryTop) {
+		if pair.Key == "" {
+			continue
+		}
+		months, err := authorMonthlyTemplates(directory, pair.Key)
+		if err != nil {
+			return err
+		}
+		series = append(series, TimeSeries{Name: pair.Key, Points: months})
+	}
+	renderSVGLineChart(series, writer)
+	return nil
+}
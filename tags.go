@@ -0,0 +1,250 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/templates-stats/pkg/templatestats"
+	sliceutil "github.com/projectdiscovery/utils/slice"
+)
+
+type TagPercentile struct {
+	Tag        string  `json:"tag"`
+	Count      int     `json:"count"`
+	Rank       int     `json:"rank"`
+	UniqueTags int     `json:"unique_tags"`
+	Percentile float64 `json:"percentile"`
+	TopPct     float64 `json:"top_pct"`
+}
+
+// normalizedTagCounts merges the counts of tags differing only by case or
+// surrounding spaces and drops the empty tag of untagged templates.
+func normalizedTagCounts(tagMap map[string]int) map[string]int {
+	counts := make(map[string]int)
+	for key, value := range tagMap {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "" {
+			continue
+		}
+		counts[key] += value
+	}
+	return counts
+}
+
+// computeTagPercentile ranks tag against the frequency of every other tag.
+// Tags sharing a count share the best rank.
+func computeTagPercentile(tagMap map[string]int, tag string) (*TagPercentile, error) {
+	counts := normalizedTagCounts(tagMap)
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	count, ok := counts[tag]
+	if !ok {
+		return nil, fmt.Errorf("tag %s not found", tag)
+	}
+
+	higher := 0
+	for _, value := range counts {
+		if value > count {
+			higher++
+		}
+	}
+	result := &TagPercentile{Tag: tag, Count: count, Rank: higher + 1, UniqueTags: len(counts)}
+	result.TopPct = math.Ceil(float64(result.Rank) * 100 / float64(result.UniqueTags))
+	result.Percentile = 100 - result.TopPct
+	return result, nil
+}
+
+func printTagPercentile(tagMap map[string]int, tag string, writer io.Writer) error {
+	result, err := computeTagPercentile(tagMap, tag)
+	if err != nil {
+		return err
+	}
+	if *jsonOutput {
+		if err := json.NewEncoder(writer).Encode(result); err != nil {
+			log.Fatalf("Could not encode json: %s\n", err)
+		}
+		return nil
+	}
+	fmt.Fprintf(writer, "%s is at the %s percentile by frequency (top %.0f%% of %d unique tags, count=%d)\n", result.Tag, ordinal(int(result.Percentile)), result.TopPct, result.UniqueTags, result.Count)
+	return nil
+}
+
+type TagEntropy struct {
+	Entropy    float64 `json:"entropy"`
+	MaxEntropy float64 `json:"max_entropy"`
+	UniqueTags int     `json:"unique_tags"`
+}
+
+// computeTagEntropy returns the shannon entropy in bits of the tag frequency
+// distribution along with the entropy of an uniform distribution.
+func computeTagEntropy(tagMap map[string]int) TagEntropy {
+	counts := normalizedTagCounts(tagMap)
+	total := 0
+	for _, value := range counts {
+		total += value
+	}
+	result := TagEntropy{UniqueTags: len(counts)}
+	for _, value := range counts {
+		p := float64(value) / float64(total)
+		result.Entropy -= p * math.Log2(p)
+	}
+	if len(counts) > 0 {
+		result.MaxEntropy = math.Log2(float64(len(counts)))
+	}
+	return result
+}
+
+func printTagEntropy(tagMap map[string]int, writer io.Writer) {
+	result := computeTagEntropy(tagMap)
+	if *jsonOutput {
+		if err := json.NewEncoder(writer).Encode(result); err != nil {
+			log.Fatalf("Could not encode json: %s\n", err)
+		}
+		return
+	}
+	fmt.Fprintf(writer, "H = %.2f bits (out of log2(N) = %.2f max bits, %d unique tags)\n", result.Entropy, result.MaxEntropy, result.UniqueTags)
+	fmt.Fprintf(writer, "The closer H is to the maximum the more evenly templates are spread across tags, a low H means a few tags dominate.\n")
+}
+
+// ordinal returns n with its english ordinal suffix (1st, 2nd, 3rd, 4th..)
+func ordinal(n int) string {
+	suffix := "th"
+	switch n % 10 {
+	case 1:
+		suffix = "st"
+	case 2:
+		suffix = "nd"
+	case 3:
+		suffix = "rd"
+	}
+	if n%100 >= 11 && n%100 <= 13 {
+		suffix = "th"
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
+
+type TagNode struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
+
+type TagEdge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Weight int    `json:"weight"`
+}
+
+type TagNetwork struct {
+	Nodes []TagNode `json:"nodes"`
+	Edges []TagEdge `json:"edges"`
+}
+
+// buildTagNetwork returns the tag co-occurrence network of the templates.
+// When n is non-zero only the n most common tags and their edges are kept.
+func buildTagNetwork(records []templateRecord, n int) *TagNetwork {
+	counts := make(map[string]int)
+	cooccurrences := make(map[[2]string]int)
+	for _, record := range records {
+		var tags []string
+		for _, tag := range templatestats.TemplateTags(record) {
+			tags = append(tags, strings.ToLower(strings.TrimSpace(tag)))
+		}
+		tags = sliceutil.Dedupe(tags)
+		sort.Strings(tags)
+		for i, tag := range tags {
+			if tag == "" {
+				continue
+			}
+			counts[tag]++
+			for _, other := range tags[i+1:] {
+				cooccurrences[[2]string{tag, other}]++
+			}
+		}
+	}
+
+	network := &TagNetwork{Nodes: []TagNode{}, Edges: []TagEdge{}}
+	included := make(map[string]struct{})
+	for _, pair := range newPairListFromMap(counts, n) {
+		included[pair.Key] = struct{}{}
+		network.Nodes = append(network.Nodes, TagNode{ID: pair.Key, Label: pair.Key, Count: pair.Value})
+	}
+	for pair, weight := range cooccurrences {
+		_, sourceOk := included[pair[0]]
+		_, targetOk := included[pair[1]]
+		if sourceOk && targetOk {
+			network.Edges = append(network.Edges, TagEdge{Source: pair[0], Target: pair[1], Weight: weight})
+		}
+	}
+	sort.Slice(network.Edges, func(i, j int) bool {
+		if network.Edges[i].Weight != network.Edges[j].Weight {
+			return network.Edges[i].Weight > network.Edges[j].Weight
+		}
+		if network.Edges[i].Source != network.Edges[j].Source {
+			return network.Edges[i].Source < network.Edges[j].Source
+		}
+		return network.Edges[i].Target < network.Edges[j].Target
+	})
+	return network
+}
+
+// writeTagNetwork writes the tag network as d3.js and gephi compatible json
+func writeTagNetwork(records []templateRecord, n int, writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(buildTagNetwork(records, n))
+}
+
+// tagCloudWeights returns the weight of every tag on a 1-100 scale relative
+// to the most used tag.
+func tagCloudWeights(tagMap map[string]int) PairList {
+	counts := normalizedTagCounts(tagMap)
+	maxCount := 0
+	for _, value := range counts {
+		if value > maxCount {
+			maxCount = value
+		}
+	}
+	weights := make(PairList, 0, len(counts))
+	for tag, value := range counts {
+		weight := int(math.Round(100 * float64(value) / float64(maxCount)))
+		if weight < 1 {
+			weight = 1
+		}
+		weights = append(weights, Pair{Key: tag, Value: weight})
+	}
+	sort.Sort(templatestats.StablePairList(weights))
+	return weights
+}
+
+// writeTagCloudCSV writes the tag,weight csv of the tag cloud weights to path
+func writeTagCloudCSV(path string, tagMap map[string]int) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "could not create tag cloud csv")
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write([]string{"tag", "weight"}); err != nil {
+		return errors.Wrap(err, "could not write tag cloud csv")
+	}
+	for _, pair := range tagCloudWeights(tagMap) {
+		if err := writer.Write([]string{pair.Key, strconv.Itoa(pair.Value)}); err != nil {
+			return errors.Wrap(err, "could not write tag cloud csv")
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return errors.Wrap(err, "could not write tag cloud csv")
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// severityColors are the 256 color ANSI codes of every severity, matching
+// #ff0000, #ff6600, #ffcc00, #00ff00 and #888888.
+var severityColors = map[string]int{
+	"critical": 196,
+	"high":     202,
+	"medium":   220,
+	"low":      46,
+	"info":     102,
+}
+
+// colorSeverity wraps severity in the 256 color ANSI escape of its color
+func colorSeverity(severity string) string {
+	color, ok := severityColors[severity]
+	if !ok {
+		return severity
+	}
+	return fmt.Sprintf("\x1b[38;5;%dm%s\x1b[0m", color, severity)
+}
+
+// stdoutSupports256Colors reports whether stdout is a terminal whose TERM
+// advertises 256 colors, the severity column is then colored by default.
+func stdoutSupports256Colors() bool {
+	return strings.HasSuffix(os.Getenv("TERM"), "256color") && term.IsTerminal(int(os.Stdout.Fd()))
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tlsCheckConcurrency is the number of hosts dialed concurrently
+const tlsCheckConcurrency = 10
+
+// tlsDialTimeout is the timeout of a single tls handshake
+const tlsDialTimeout = 10 * time.Second
+
+type TLSError struct {
+	Path  string `json:"path"`
+	URL   string `json:"url"`
+	Error string `json:"error"`
+}
+
+// referenceHost returns the host:port dialed for an https reference
+func referenceHost(reference string) (string, bool) {
+	parsed, err := url.Parse(strings.TrimSpace(reference))
+	if err != nil || !strings.EqualFold(parsed.Scheme, "https") || parsed.Hostname() == "" {
+		return "", false
+	}
+	port := parsed.Port()
+	if port == "" {
+		port = "443"
+	}
+	return net.JoinHostPort(parsed.Hostname(), port), true
+}
+
+// dialTLS performs a tls handshake with host, the certificate chain and
+// expiry are verified unless insecure is set.
+func dialTLS(host string, insecure bool) error {
+	dialer := &net.Dialer{Timeout: tlsDialTimeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", host, &tls.Config{InsecureSkipVerify: insecure})
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// findTLSErrors dials the host of every https reference once and returns
+// the references whose tls connection failed.
+func findTLSErrors(records []templateRecord, insecure bool) []TLSError {
+	hostReferences := make(map[string][]TLSError)
+	for _, record := range records {
+		for _, reference := range templateReferences(record) {
+			host, ok := referenceHost(reference)
+			if !ok {
+				continue
+			}
+			hostReferences[host] = append(hostReferences[host], TLSError{Path: record.Path, URL: reference})
+		}
+	}
+
+	var (
+		mutex    sync.Mutex
+		wg       sync.WaitGroup
+		failures []TLSError
+	)
+	semaphore := make(chan struct{}, tlsCheckConcurrency)
+	for host, references := range hostReferences {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(host string, references []TLSError) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			err := dialTLS(host, insecure)
+			if err == nil {
+				return
+			}
+			mutex.Lock()
+			for _, reference := range references {
+				reference.Error = err.Error()
+				failures = append(failures, reference)
+			}
+			mutex.Unlock()
+		}(host, references)
+	}
+	wg.Wait()
+
+	sort.Slice(failures, func(i, j int) bool {
+		if failures[i].Path != failures[j].Path {
+			return failures[i].Path < failures[j].Path
+		}
+		return failures[i].URL < failures[j].URL
+	})
+	return failures
+}
+
+func printTLSErrors(records []templateRecord, insecure bool, writer io.Writer) {
+	tlsErrors := findTLSErrors(records, insecure)
+
+	rows := make([][]string, 0, len(tlsErrors))
+	for _, item := range tlsErrors {
+		rows = append(rows, []string{item.Path, item.URL, item.Error})
+	}
+	writeReport(writer, tlsErrors, []string{"Path", "URL", "Error"}, rows)
+}
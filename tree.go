@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+)
+
+// TrieNode is a directory of the template tree along with the number of
+// templates below it.
+type TrieNode struct {
+	Name     string      `json:"name"`
+	Count    int         `json:"count"`
+	Children []*TrieNode `json:"children,omitempty"`
+
+	children map[string]*TrieNode
+}
+
+func newTrieNode(name string) *TrieNode {
+	return &TrieNode{Name: name, children: make(map[string]*TrieNode)}
+}
+
+// insert counts a template under every directory of parts
+func (n *TrieNode) insert(parts []string) {
+	n.Count++
+	if len(parts) == 0 {
+		return
+	}
+	child, ok := n.children[parts[0]]
+	if !ok {
+		child = newTrieNode(parts[0])
+		n.children[parts[0]] = child
+	}
+	child.insert(parts[1:])
+}
+
+// sortChildren orders the children of every node by template count
+func (n *TrieNode) sortChildren() {
+	n.Children = make([]*TrieNode, 0, len(n.children))
+	for _, child := range n.children {
+		child.sortChildren()
+		n.Children = append(n.Children, child)
+	}
+	sort.Slice(n.Children, func(i, j int) bool {
+		if n.Children[i].Count != n.Children[j].Count {
+			return n.Children[i].Count > n.Children[j].Count
+		}
+		return n.Children[i].Name < n.Children[j].Name
+	})
+}
+
+// buildDirectoryTrie builds the directory tree of the templates up to
+// depth levels below the template directory, a depth of 0 is unlimited.
+func buildDirectoryTrie(records []templateRecord, directory string, depth int) *TrieNode {
+	root := newTrieNode("")
+	for _, record := range records {
+		parts := strings.Split(templateRelativePath(directory, record.Path), "/")
+		parts = parts[:len(parts)-1]
+		if depth > 0 && len(parts) > depth {
+			parts = parts[:depth]
+		}
+		root.insert(parts)
+	}
+	root.sortChildren()
+	return root
+}
+
+// renderTree writes the children of root as an indented directory tree
+func renderTree(root *TrieNode, writer io.Writer) {
+	var render func(node *TrieNode, indent int)
+	render = func(node *TrieNode, indent int) {
+		for _, child := range node.Children {
+			fmt.Fprintf(writer, "%s%s/ (%d)\n", strings.Repeat("  ", indent), child.Name, child.Count)
+			render(child, indent+1)
+		}
+	}
+	render(root, 0)
+}
+
+func printDirectoryTree(records []templateRecord, directory string, depth int, writer io.Writer) {
+	root := buildDirectoryTrie(records, directory, depth)
+	if *jsonOutput {
+		if err := json.NewEncoder(writer).Encode(root.Children); err != nil {
+			log.Fatalf("Could not encode json: %s\n", err)
+		}
+		return
+	}
+	renderTree(root, writer)
+}
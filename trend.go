@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+)
+
+type QuarterSeverity struct {
+	Quarter       string `json:"quarter"`
+	CriticalCount int    `json:"critical_count"`
+	HighCount     int    `json:"high_count"`
+	MediumCount   int    `json:"medium_count"`
+	LowCount      int    `json:"low_count"`
+	InfoCount     int    `json:"info_count"`
+}
+
+// quarterOf returns the YYYY-Q# calendar quarter of t
+func quarterOf(t time.Time) string {
+	return fmt.Sprintf("%d-Q%d", t.Year(), (int(t.Month())-1)/3+1)
+}
+
+// computeSeverityTrend buckets the templates by the quarter of their
+// modification time and counts their severities.
+func computeSeverityTrend(records []templateRecord) []QuarterSeverity {
+	quarters := make(map[string]*QuarterSeverity)
+	for _, record := range records {
+		quarter := quarterOf(record.ModTime)
+		item, ok := quarters[quarter]
+		if !ok {
+			item = &QuarterSeverity{Quarter: quarter}
+			quarters[quarter] = item
+		}
+		switch strings.ToLower(types.ToString(record.Info["severity"])) {
+		case "critical":
+			item.CriticalCount++
+		case "high":
+			item.HighCount++
+		case "medium":
+			item.MediumCount++
+		case "low":
+			item.LowCount++
+		case "info":
+			item.InfoCount++
+		}
+	}
+
+	trend := make([]QuarterSeverity, 0, len(quarters))
+	for _, item := range quarters {
+		trend = append(trend, *item)
+	}
+	sort.Slice(trend, func(i, j int) bool { return trend[i].Quarter < trend[j].Quarter })
+	return trend
+}
+
+func printSeverityTrend(records []templateRecord, writer io.Writer) {
+	trend := computeSeverityTrend(records)
+
+	rows := make([][]string, 0, len(trend))
+	for _, item := range trend {
+		rows = append(rows, []string{
+			item.Quarter,
+			strconv.Itoa(item.CriticalCount),
+			strconv.Itoa(item.HighCount),
+			strconv.Itoa(item.MediumCount),
+			strconv.Itoa(item.LowCount),
+			strconv.Itoa(item.InfoCount),
+		})
+	}
+	writeReport(writer, trend, []string{"Quarter", "Critical", "High", "Medium", "Low", "Info"}, rows)
+}
+
+// freshnessWindow is the period a template is considered recently updated in
+const freshnessWindow = 90 * 24 * time.Hour
+
+type DirectoryFreshness struct {
+	Directory      string  `json:"directory"`
+	Total          int     `json:"total"`
+	Recent90d      int     `json:"recent_90d"`
+	FreshnessScore float64 `json:"freshness_score"`
+}
+
+// computeDirectoryFreshness returns the ratio of templates modified within
+// the freshness window per top level directory, the stalest first.
+func computeDirectoryFreshness(records []templateRecord, directory string, now time.Time) []DirectoryFreshness {
+	directories := make(map[string]*DirectoryFreshness)
+	for _, record := range records {
+		dir := topLevelDirectory(directory, record.Path)
+		item, ok := directories[dir]
+		if !ok {
+			item = &DirectoryFreshness{Directory: dir}
+			directories[dir] = item
+		}
+		item.Total++
+		if now.Sub(record.ModTime) <= freshnessWindow {
+			item.Recent90d++
+		}
+	}
+
+	freshness := make([]DirectoryFreshness, 0, len(directories))
+	for _, item := range directories {
+		item.FreshnessScore = float64(item.Recent90d) / float64(item.Total)
+		freshness = append(freshness, *item)
+	}
+	sort.Slice(freshness, func(i, j int) bool {
+		if freshness[i].FreshnessScore != freshness[j].FreshnessScore {
+			return freshness[i].FreshnessScore < freshness[j].FreshnessScore
+		}
+		return freshness[i].Directory < freshness[j].Directory
+	})
+	return freshness
+}
+
+func printDirectoryFreshness(records []templateRecord, directory string, writer io.Writer) {
+	freshness := computeDirectoryFreshness(records, directory, time.Now())
+
+	rows := make([][]string, 0, len(freshness))
+	for _, item := range freshness {
+		rows = append(rows, []string{item.Directory, strconv.Itoa(item.Total), strconv.Itoa(item.Recent90d), fmt.Sprintf("%.2f", item.FreshnessScore)})
+	}
+	writeReport(writer, freshness, []string{"Directory", "Total", "Recent 90d", "Freshness"}, rows)
+}
+
+type SeverityAge struct {
+	Severity   string  `json:"severity"`
+	AvgAgeDays float64 `json:"avg_age_days"`
+	OldestPath string  `json:"oldest_path"`
+	NewestPath string  `json:"newest_path"`
+}
+
+// computeSeverityAge returns the average days since the modification of the
+// templates of every severity along with the oldest and newest template.
+func computeSeverityAge(records []templateRecord, now time.Time) []SeverityAge {
+	type severityBucket struct {
+		ages           []float64
+		oldest, newest templateRecord
+	}
+	buckets := make(map[string]*severityBucket)
+	for _, record := range records {
+		severity := strings.ToLower(types.ToString(record.Info["severity"]))
+		if severity == "" {
+			continue
+		}
+		bucket, ok := buckets[severity]
+		if !ok {
+			bucket = &severityBucket{oldest: record, newest: record}
+			buckets[severity] = bucket
+		}
+		bucket.ages = append(bucket.ages, now.Sub(record.ModTime).Hours()/24)
+		if record.ModTime.Before(bucket.oldest.ModTime) {
+			bucket.oldest = record
+		}
+		if record.ModTime.After(bucket.newest.ModTime) {
+			bucket.newest = record
+		}
+	}
+
+	ages := make([]SeverityAge, 0, len(buckets))
+	for severity, bucket := range buckets {
+		ages = append(ages, SeverityAge{
+			Severity:   severity,
+			AvgAgeDays: mean(bucket.ages),
+			OldestPath: bucket.oldest.Path,
+			NewestPath: bucket.newest.Path,
+		})
+	}
+	sort.Slice(ages, func(i, j int) bool {
+		if first, second := severityRank(ages[i].Severity), severityRank(ages[j].Severity); first != second {
+			return first > second
+		}
+		return ages[i].Severity < ages[j].Severity
+	})
+	return ages
+}
+
+func printSeverityAge(records []templateRecord, writer io.Writer) {
+	ages := computeSeverityAge(records, time.Now())
+
+	rows := make([][]string, 0, len(ages))
+	for _, age := range ages {
+		rows = append(rows, []string{age.Severity, fmt.Sprintf("%.1f", age.AvgAgeDays), age.OldestPath, age.NewestPath})
+	}
+	writeReport(writer, ages, []string{"Severity", "Avg Age (Days)", "Oldest", "Newest"}, rows)
+}
@@ -0,0 +1,472 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+	"github.com/projectdiscovery/templates-stats/pkg/templatestats"
+	sliceutil "github.com/projectdiscovery/utils/slice"
+)
+
+type StructuralViolation struct {
+	Path          string   `json:"path"`
+	MissingFields []string `json:"missing_fields"`
+}
+
+// templateRequestTypes returns the request type keys declared by a template
+func templateRequestTypes(record templateRecord) []string {
+	var found []string
+	for _, key := range templatestats.RequestTypeKeys {
+		if _, ok := record.Data[key]; ok {
+			found = append(found, key)
+		}
+	}
+	return found
+}
+
+// validateTemplateStructure returns the required fields missing from a template
+func validateTemplateStructure(record templateRecord) []string {
+	var missing []string
+	if len(templateRequestTypes(record)) == 0 {
+		missing = append(missing, "request type")
+	}
+	for _, field := range []string{"name", "author"} {
+		if value, ok := record.Info[field]; !ok || types.ToString(value) == "" {
+			missing = append(missing, "info."+field)
+		}
+	}
+	severity, ok := record.Info["severity"]
+	if !ok || !templatestats.IsValidSeverity(strings.ToLower(types.ToString(severity))) {
+		missing = append(missing, "info.severity")
+	}
+	return missing
+}
+
+func findStructuralViolations(records []templateRecord) []StructuralViolation {
+	var violations []StructuralViolation
+	for _, record := range records {
+		if missing := validateTemplateStructure(record); len(missing) > 0 {
+			violations = append(violations, StructuralViolation{Path: record.Path, MissingFields: missing})
+		}
+	}
+	sort.Slice(violations, func(i, j int) bool { return violations[i].Path < violations[j].Path })
+	return violations
+}
+
+func printStructuralViolations(records []templateRecord, writer io.Writer) {
+	violations := findStructuralViolations(records)
+
+	rows := make([][]string, 0, len(violations))
+	for _, violation := range violations {
+		rows = append(rows, []string{violation.Path, strings.Join(violation.MissingFields, ", ")})
+	}
+	writeReport(writer, violations, []string{"Path", "Missing Fields"}, rows)
+}
+
+// infoFields are the info block fields checked by -check-missing-info-fields
+var infoFields = []string{"name", "description", "tags", "author", "severity", "reference"}
+
+// maxMissingFieldSamples is the number of sample paths kept per missing field
+const maxMissingFieldSamples = 10
+
+type MissingInfoField struct {
+	Field   string   `json:"field"`
+	Count   int      `json:"count"`
+	Samples []string `json:"samples,omitempty"`
+}
+
+func findMissingInfoFields(records []templateRecord) []MissingInfoField {
+	missing := make([]MissingInfoField, len(infoFields))
+	for i, field := range infoFields {
+		missing[i].Field = field
+	}
+	for _, record := range records {
+		for i, field := range infoFields {
+			if value, ok := record.Info[field]; ok && value != nil {
+				continue
+			}
+			missing[i].Count++
+			if len(missing[i].Samples) < maxMissingFieldSamples {
+				missing[i].Samples = append(missing[i].Samples, record.Path)
+			}
+		}
+	}
+	sort.SliceStable(missing, func(i, j int) bool { return missing[i].Count > missing[j].Count })
+	return missing
+}
+
+func printMissingInfoFields(records []templateRecord, writer io.Writer) {
+	missing := findMissingInfoFields(records)
+
+	rows := make([][]string, 0, len(missing))
+	for _, field := range missing {
+		rows = append(rows, []string{field.Field, strconv.Itoa(field.Count), strings.Join(field.Samples, "\n")})
+	}
+	writeReport(writer, missing, []string{"Field", "Missing", "Samples"}, rows)
+}
+
+type AuthorFormatViolation struct {
+	Path    string `json:"path"`
+	Author  string `json:"author"`
+	Pattern string `json:"pattern"`
+}
+
+// findAuthorFormatViolations returns the authors not matching pattern
+func findAuthorFormatViolations(records []templateRecord, pattern *regexp.Regexp) []AuthorFormatViolation {
+	var violations []AuthorFormatViolation
+	for _, record := range records {
+		author, ok := record.Info["author"]
+		if !ok {
+			continue
+		}
+		for _, part := range strings.Split(types.ToString(author), ",") {
+			part = strings.TrimSpace(part)
+			if !pattern.MatchString(part) {
+				violations = append(violations, AuthorFormatViolation{Path: record.Path, Author: part, Pattern: pattern.String()})
+			}
+		}
+	}
+	sort.SliceStable(violations, func(i, j int) bool { return violations[i].Path < violations[j].Path })
+	return violations
+}
+
+func printAuthorFormatViolations(records []templateRecord, pattern string, writer io.Writer) error {
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return errors.Wrap(err, "could not compile author format")
+	}
+	violations := findAuthorFormatViolations(records, compiled)
+
+	rows := make([][]string, 0, len(violations))
+	for _, violation := range violations {
+		rows = append(rows, []string{violation.Path, violation.Author})
+	}
+	writeReport(writer, violations, []string{"Path", "Author"}, rows)
+	return nil
+}
+
+type ReferenceCountViolation struct {
+	Path  string `json:"path"`
+	Count int    `json:"count"`
+	Limit int    `json:"limit"`
+}
+
+// findReferenceCountViolations returns the templates with fewer than min or
+// more than max references. A zero limit disables the check.
+func findReferenceCountViolations(records []templateRecord, min, max int) []ReferenceCountViolation {
+	var violations []ReferenceCountViolation
+	for _, record := range records {
+		count := len(templateReferences(record))
+		if min > 0 && count < min {
+			violations = append(violations, ReferenceCountViolation{Path: record.Path, Count: count, Limit: min})
+		}
+		if max > 0 && count > max {
+			violations = append(violations, ReferenceCountViolation{Path: record.Path, Count: count, Limit: max})
+		}
+	}
+	sort.Slice(violations, func(i, j int) bool { return violations[i].Path < violations[j].Path })
+	return violations
+}
+
+func printReferenceCountViolations(records []templateRecord, writer io.Writer) {
+	violations := findReferenceCountViolations(records, *minReferences, *maxReferences)
+
+	rows := make([][]string, 0, len(violations))
+	for _, violation := range violations {
+		rows = append(rows, []string{violation.Path, strconv.Itoa(violation.Count), strconv.Itoa(violation.Limit)})
+	}
+	writeReport(writer, violations, []string{"Path", "References", "Limit"}, rows)
+}
+
+type TagCountViolation struct {
+	Path  string `json:"path"`
+	Count int    `json:"count"`
+	Limit int    `json:"limit"`
+}
+
+// uniqueTemplateTags returns the tags of a template without repeated entries
+func uniqueTemplateTags(record templateRecord) []string {
+	return sliceutil.Dedupe(templatestats.TemplateTags(record))
+}
+
+// findTagCountViolations returns the templates with fewer than min or more
+// than max unique tags. A zero limit disables the check.
+func findTagCountViolations(records []templateRecord, min, max int) []TagCountViolation {
+	var violations []TagCountViolation
+	for _, record := range records {
+		count := len(uniqueTemplateTags(record))
+		if min > 0 && count < min {
+			violations = append(violations, TagCountViolation{Path: record.Path, Count: count, Limit: min})
+		}
+		if max > 0 && count > max {
+			violations = append(violations, TagCountViolation{Path: record.Path, Count: count, Limit: max})
+		}
+	}
+	sort.Slice(violations, func(i, j int) bool { return violations[i].Path < violations[j].Path })
+	return violations
+}
+
+func printTagCountViolations(records []templateRecord, writer io.Writer) {
+	violations := findTagCountViolations(records, *minTags, *maxTags)
+
+	rows := make([][]string, 0, len(violations))
+	for _, violation := range violations {
+		rows = append(rows, []string{violation.Path, strconv.Itoa(violation.Count), strconv.Itoa(violation.Limit)})
+	}
+	writeReport(writer, violations, []string{"Path", "Tags", "Limit"}, rows)
+}
+
+type ExcessiveTagsViolation struct {
+	Path     string   `json:"path"`
+	TagCount int      `json:"tag_count"`
+	Tags     []string `json:"tags"`
+}
+
+// findExcessiveTags returns the templates with more than max unique tags
+// along with their tag list.
+func findExcessiveTags(records []templateRecord, max int) []ExcessiveTagsViolation {
+	tags := make(map[string][]string, len(records))
+	for _, record := range records {
+		tags[record.Path] = uniqueTemplateTags(record)
+	}
+	var violations []ExcessiveTagsViolation
+	for _, violation := range findTagCountViolations(records, 0, max) {
+		violations = append(violations, ExcessiveTagsViolation{Path: violation.Path, TagCount: violation.Count, Tags: tags[violation.Path]})
+	}
+	return violations
+}
+
+func printExcessiveTags(records []templateRecord, max int, writer io.Writer) {
+	violations := findExcessiveTags(records, max)
+
+	rows := make([][]string, 0, len(violations))
+	for _, violation := range violations {
+		rows = append(rows, []string{violation.Path, strconv.Itoa(violation.TagCount), strings.Join(violation.Tags, ",")})
+	}
+	writeReport(writer, violations, []string{"Path", "Tags", "Tag List"}, rows)
+}
+
+type ExcessiveAuthorsViolation struct {
+	Path    string   `json:"path"`
+	Count   int      `json:"count"`
+	Authors []string `json:"authors"`
+}
+
+// findExcessiveAuthors returns the templates listing more than max authors
+func findExcessiveAuthors(records []templateRecord, max int) []ExcessiveAuthorsViolation {
+	var violations []ExcessiveAuthorsViolation
+	for _, record := range records {
+		authors := templatestats.ExplodeCommaSeparatedField(types.ToString(record.Info["author"]))
+		if len(authors) > max {
+			violations = append(violations, ExcessiveAuthorsViolation{Path: record.Path, Count: len(authors), Authors: authors})
+		}
+	}
+	sort.Slice(violations, func(i, j int) bool { return violations[i].Path < violations[j].Path })
+	return violations
+}
+
+func printExcessiveAuthors(records []templateRecord, max int, writer io.Writer) {
+	violations := findExcessiveAuthors(records, max)
+
+	rows := make([][]string, 0, len(violations))
+	for _, violation := range violations {
+		rows = append(rows, []string{violation.Path, strconv.Itoa(violation.Count), strings.Join(violation.Authors, ",")})
+	}
+	writeReport(writer, violations, []string{"Path", "Authors", "Author List"}, rows)
+}
+
+// lintLevelRank orders the lint levels from the most severe
+var lintLevelRank = map[string]int{templatestats.LintError: 0, templatestats.LintWarning: 1, templatestats.LintInfo: 2}
+
+// collectLintIssues merges the issues found while parsing with the templates
+// carrying more than maxTags tags, reported on the tags-count field.
+func collectLintIssues(issues []LintIssue, records []templateRecord, maxTags int) []LintIssue {
+	report := append([]LintIssue{}, issues...)
+	for _, violation := range findExcessiveTags(records, maxTags) {
+		report = append(report, LintIssue{Path: violation.Path, Field: "tags-count", Level: templatestats.LintWarning})
+	}
+	sort.SliceStable(report, func(i, j int) bool {
+		if report[i].Path != report[j].Path {
+			return report[i].Path < report[j].Path
+		}
+		return lintLevelRank[report[i].Level] < lintLevelRank[report[j].Level]
+	})
+	return report
+}
+
+// printLintReport writes the lint issues and returns the number of errors
+func printLintReport(issues []LintIssue, records []templateRecord, maxTags int, writer io.Writer) int {
+	report := collectLintIssues(issues, records, maxTags)
+
+	errorCount := 0
+	rows := make([][]string, 0, len(report))
+	for _, issue := range report {
+		if issue.Level == templatestats.LintError {
+			errorCount++
+		}
+		rows = append(rows, []string{issue.Path, issue.Field, issue.Level})
+	}
+	writeReport(writer, report, []string{"Path", "Field", "Level"}, rows)
+	return errorCount
+}
+
+func printDuplicateIDs(duplicates []DuplicateEntry, writer io.Writer) {
+	for _, duplicate := range duplicates {
+		fmt.Fprintf(writer, "[duplicate] %s declared by %s\n", duplicate.ID, strings.Join(duplicate.Paths, ", "))
+	}
+}
+
+type MissingCVEReference struct {
+	Path  string `json:"path"`
+	CVEID string `json:"cve_id"`
+}
+
+// findMissingCVEReferences returns the CVE templates without any reference
+// url containing their CVE id.
+func findMissingCVEReferences(records []templateRecord) []MissingCVEReference {
+	var missing []MissingCVEReference
+	for _, record := range records {
+		if !isCveTemplate(record) {
+			continue
+		}
+		cveID := strings.ToUpper(record.ID)
+		found := false
+		for _, reference := range templateReferences(record) {
+			if strings.Contains(strings.ToUpper(reference), cveID) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, MissingCVEReference{Path: record.Path, CVEID: record.ID})
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i].CVEID < missing[j].CVEID })
+	return missing
+}
+
+func printMissingCVEReferences(records []templateRecord, writer io.Writer) {
+	missing := findMissingCVEReferences(records)
+
+	rows := make([][]string, 0, len(missing))
+	for _, item := range missing {
+		rows = append(rows, []string{item.CVEID, item.Path})
+	}
+	writeReport(writer, missing, []string{"CVE ID", "Path"}, rows)
+}
+
+type SelfReference struct {
+	Path      string `json:"path"`
+	Reference string `json:"reference"`
+}
+
+// isSelfReference reports whether a reference url points to the template
+// itself, e.g. a github blob or raw url ending with its relative path.
+func isSelfReference(reference, relativePath string) bool {
+	parsed, err := url.Parse(strings.TrimSpace(reference))
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(parsed.Hostname())
+	if host != "github.com" && host != "raw.githubusercontent.com" {
+		return false
+	}
+	return strings.HasSuffix(strings.ToLower(parsed.Path), "/"+strings.ToLower(relativePath))
+}
+
+// findSelfReferences returns the reference urls pointing to their own template
+func findSelfReferences(records []templateRecord, directory string) []SelfReference {
+	var references []SelfReference
+	for _, record := range records {
+		relativePath := templateRelativePath(directory, record.Path)
+		for _, reference := range templateReferences(record) {
+			if isSelfReference(reference, relativePath) {
+				references = append(references, SelfReference{Path: record.Path, Reference: reference})
+			}
+		}
+	}
+	sort.SliceStable(references, func(i, j int) bool { return references[i].Path < references[j].Path })
+	return references
+}
+
+func printSelfReferences(records []templateRecord, directory string, writer io.Writer) {
+	references := findSelfReferences(records, directory)
+
+	rows := make([][]string, 0, len(references))
+	for _, reference := range references {
+		rows = append(rows, []string{reference.Path, reference.Reference})
+	}
+	writeReport(writer, references, []string{"Path", "Reference"}, rows)
+}
+
+type BrokenWorkflowRef struct {
+	WorkflowPath string `json:"workflow_path"`
+	MissingID    string `json:"missing_id"`
+}
+
+// workflowTemplateRefs returns every template referenced by a workflow
+// including the subtemplates of its matchers.
+func workflowTemplateRefs(value interface{}) []string {
+	var refs []string
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			refs = append(refs, workflowTemplateRefs(item)...)
+		}
+	case map[interface{}]interface{}:
+		if template := types.ToString(v["template"]); template != "" {
+			refs = append(refs, template)
+		}
+		refs = append(refs, workflowTemplateRefs(v["subtemplates"])...)
+		refs = append(refs, workflowTemplateRefs(v["matchers"])...)
+	}
+	return refs
+}
+
+// findBrokenWorkflowRefs returns the workflow references which match neither
+// a template id, a template path nor a template directory relative to directory.
+func findBrokenWorkflowRefs(records []templateRecord, directory string) []BrokenWorkflowRef {
+	known := make(map[string]struct{}, len(records)*2)
+	for _, record := range records {
+		known[record.ID] = struct{}{}
+		known[templateRelativePath(directory, record.Path)] = struct{}{}
+	}
+
+	var broken []BrokenWorkflowRef
+	for _, record := range records {
+		workflows, ok := record.Data["workflows"]
+		if !ok {
+			continue
+		}
+		for _, ref := range workflowTemplateRefs(workflows) {
+			if _, ok := known[strings.TrimPrefix(filepath.ToSlash(ref), "./")]; ok {
+				continue
+			}
+			if stat, err := os.Stat(filepath.Join(directory, ref)); err == nil && stat.IsDir() {
+				continue
+			}
+			broken = append(broken, BrokenWorkflowRef{WorkflowPath: record.Path, MissingID: ref})
+		}
+	}
+	sort.SliceStable(broken, func(i, j int) bool { return broken[i].WorkflowPath < broken[j].WorkflowPath })
+	return broken
+}
+
+func printBrokenWorkflowRefs(records []templateRecord, directory string, writer io.Writer) {
+	broken := findBrokenWorkflowRefs(records, directory)
+
+	rows := make([][]string, 0, len(broken))
+	for _, item := range broken {
+		rows = append(rows, []string{item.WorkflowPath, item.MissingID})
+	}
+	writeReport(writer, broken, []string{"Workflow", "Missing Template"}, rows)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// version is the release version, set at build time with
+// -ldflags "-X main.version=v1.0.0"
+var version = "dev"
+
+// buildVersion returns the version and build date of the binary. The module
+// version is used when built with go install and no version was injected.
+func buildVersion() (string, string) {
+	buildVersion, buildDate := version, "unknown"
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return buildVersion, buildDate
+	}
+	if buildVersion == "dev" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		buildVersion = info.Main.Version
+	}
+	for _, setting := range info.Settings {
+		if setting.Key != "vcs.time" {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339, setting.Value); err == nil {
+			buildDate = t.Format("2006-01-02")
+		}
+	}
+	return buildVersion, buildDate
+}
+
+func printVersion(writer io.Writer) {
+	buildVersion, buildDate := buildVersion()
+	fmt.Fprintf(writer, "templates-stats version %s built %s with %s\n", buildVersion, buildDate, runtime.Version())
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/types"
+	stringsutil "github.com/projectdiscovery/utils/strings"
+)
+
+var webhookClient = &http.Client{Timeout: 10 * time.Second}
+
+type authorEvent struct {
+	Event         string `json:"event"`
+	Author        string `json:"author"`
+	TemplateCount int    `json:"template_count"`
+}
+
+// loadKnownAuthors reads the author state file. A missing file returns a
+// nil set so the first run only records the current authors.
+func loadKnownAuthors(path string) (map[string]struct{}, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read authors state")
+	}
+	var authors []string
+	if err := json.Unmarshal(data, &authors); err != nil {
+		return nil, errors.Wrap(err, "could not decode authors state")
+	}
+	known := make(map[string]struct{}, len(authors))
+	for _, author := range authors {
+		known[author] = struct{}{}
+	}
+	return known, nil
+}
+
+func saveKnownAuthors(path string, authorMap map[string]int) error {
+	authors := make([]string, 0, len(authorMap))
+	for author := range authorMap {
+		if author != "" {
+			authors = append(authors, author)
+		}
+	}
+	sort.Strings(authors)
+
+	data, err := json.MarshalIndent(authors, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "could not encode authors state")
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return errors.Wrap(err, "could not write authors state")
+	}
+	return nil
+}
+
+func postWebhook(webhookURL string, event authorEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "could not encode webhook event")
+	}
+	resp, err := webhookClient.Post(webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "could not send webhook")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected webhook status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// watchAuthors reports authors not present in the state file and updates it
+func watchAuthors(statePath, webhookURL string, authorMap map[string]int) error {
+	known, err := loadKnownAuthors(statePath)
+	if err != nil {
+		return err
+	}
+	if known != nil {
+		for _, pair := range newPairListFromMap(authorMap, 0) {
+			if _, ok := known[pair.Key]; ok || pair.Key == "" {
+				continue
+			}
+			log.Printf("[new author] %s (%d templates)\n", pair.Key, pair.Value)
+			if webhookURL == "" {
+				continue
+			}
+			if err := postWebhook(webhookURL, authorEvent{Event: "new_author", Author: pair.Key, TemplateCount: pair.Value}); err != nil {
+				log.Printf("Could not notify new author %s: %s\n", pair.Key, err)
+			}
+		}
+	}
+	return saveKnownAuthors(statePath, authorMap)
+}
+
+// addWatchDirectories adds directory and all of its subdirectories to watcher
+func addWatchDirectories(watcher *fsnotify.Watcher, directory string) error {
+	return filepath.WalkDir(directory, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") && path != directory {
+			return filepath.SkipDir
+		}
+		return watcher.Add(path)
+	})
+}
+
+// formatCVEEvent returns the feed line of a new CVE template
+func formatCVEEvent(record templateRecord) string {
+	authors := templateAuthors(record)
+	for i, author := range authors {
+		authors[i] = "@" + author
+	}
+	return fmt.Sprintf("[new CVE] %s: %s (%s) by %s\n", record.ID, types.ToString(record.Info["name"]), strings.ToLower(types.ToString(record.Info["severity"])), strings.Join(authors, ", "))
+}
+
+// watchNewCVEs watches directory for created or modified CVE templates and
+// writes a line for each of them until the watcher fails.
+func watchNewCVEs(directory string, writer io.Writer) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errors.Wrap(err, "could not create watcher")
+	}
+	defer watcher.Close()
+
+	if err := addWatchDirectories(watcher, directory); err != nil {
+		return errors.Wrap(err, "could not watch directory")
+	}
+	log.Printf("Watching %s for new CVE templates\n", directory)
+
+	seen := make(map[string]struct{})
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if !event.Has(fsnotify.Create) && !event.Has(fsnotify.Write) {
+				continue
+			}
+			if stat, err := os.Stat(event.Name); err == nil && stat.IsDir() {
+				if err := addWatchDirectories(watcher, event.Name); err != nil {
+					log.Printf("Could not watch %s: %s\n", event.Name, err)
+				}
+				continue
+			}
+			if !stringsutil.EqualFoldAny(filepath.Ext(event.Name), ".yaml") {
+				continue
+			}
+			// the file may still be partially written, incomplete templates
+			// are picked up again by the following write event
+			data, err := parseTemplateFile(event.Name)
+			if err != nil {
+				continue
+			}
+			id := types.ToString(data["id"])
+			if !strings.HasPrefix(id, "CVE-") {
+				continue
+			}
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			infoMap, ok := data["info"].(map[interface{}]interface{})
+			if !ok || len(validateTemplateStructure(templateRecord{Data: data, Info: infoMap})) > 0 {
+				continue
+			}
+			seen[id] = struct{}{}
+			fmt.Fprint(writer, formatCVEEvent(templateRecord{Path: event.Name, ID: id, Data: data, Info: infoMap}))
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return errors.Wrap(err, "could not watch directory")
+		}
+	}
+}